@@ -34,6 +34,16 @@ func (r rw0) Rewrite(t []scan.Token) []scan.Token {
 	return tokens
 }
 
+// rw1 is a scan.Command that rewrites the symbol to a function call with the remaining tokens.
+// Example:
+//	/save `file	is rewritten to a→save `file
+type rw1 string
+
+func (r rw1) Rewrite(t []scan.Token) []scan.Token {
+	sym := scan.Token{T: scan.Identifier, S: "a→" + string(r)}
+	return append([]scan.Token{sym}, t...)
+}
+
 // printvar prints a string representation of the value.
 // If the value is a string that is a valid variable name, it is dereferenced.
 // This allows to print the definition of lambda functions.
@@ -0,0 +1,68 @@
+package a
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ktye/iv/apl"
+)
+
+// debugger returns the debugger of the interpreter and installs one, if it is missing.
+func debugger(a *apl.Apl) *apl.Debugger {
+	if a.Debugger == nil {
+		a.Debugger = &apl.Debugger{}
+	}
+	return a.Debugger
+}
+
+// trace switches tracing of lambda functions on or off.
+// The trace is written to the output of the interpreter.
+func trace(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	n, ok := R.(apl.Number)
+	if ok == false {
+		return nil, fmt.Errorf("a trace: argument must be 0 or 1: %T", R)
+	}
+	b, ok := a.Tower.ToBool(n)
+	if ok == false {
+		return nil, fmt.Errorf("a trace: argument must be 0 or 1")
+	}
+	d := debugger(a)
+	d.Trace = nil
+	if b {
+		d.Trace = a.GetOutput()
+	}
+	return apl.EmptyArray{}, nil
+}
+
+// breakpoints sets breakpoints on the lambda functions with the given names.
+// An empty argument clears all breakpoints.
+// It returns the names of all breakpoints.
+func breakpoints(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	d := debugger(a)
+	switch v := R.(type) {
+	case apl.String:
+		if d.Breakpoints == nil {
+			d.Breakpoints = make(map[string]bool)
+		}
+		d.Breakpoints[string(v)] = true
+	case apl.StringArray:
+		if d.Breakpoints == nil {
+			d.Breakpoints = make(map[string]bool)
+		}
+		for _, s := range v.Strings {
+			d.Breakpoints[s] = true
+		}
+	default:
+		if ar, ok := R.(apl.Array); ok && ar.Size() == 0 {
+			d.Breakpoints = nil
+			break
+		}
+		return nil, fmt.Errorf("a break: argument must be a name or empty: %T", R)
+	}
+	names := make([]string, 0, len(d.Breakpoints))
+	for s := range d.Breakpoints {
+		names = append(names, s)
+	}
+	sort.Strings(names)
+	return apl.StringArray{Dims: []int{len(names)}, Strings: names}, nil
+}
@@ -0,0 +1,23 @@
+package a
+
+import (
+	"fmt"
+
+	"github.com/ktye/iv/apl"
+)
+
+// nested switches nested arrays on or off.
+// It returns the previous setting.
+func nested(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	n, ok := R.(apl.Number)
+	if ok == false {
+		return nil, fmt.Errorf("a nested: argument must be 0 or 1: %T", R)
+	}
+	b, ok := a.Tower.ToBool(n)
+	if ok == false {
+		return nil, fmt.Errorf("a nested: argument must be 0 or 1")
+	}
+	prev := a.Nested
+	a.Nested = bool(b)
+	return apl.Bool(prev), nil
+}
@@ -7,6 +7,28 @@
 //	g 0    return number of go routines
 //	m 0    return runtime.MemStats as a dictionary
 //	v 0    return go version
+//
+// Workspaces are saved and loaded with:
+//
+//	save `file   save the interpreter state to a file
+//	load `file   replace the interpreter state with the saved workspace
+//
+// or with the commands /save `file and /load `file.
+//
+// Lambda functions can be traced and stopped at breakpoints:
+//
+//	trace 1        log each call, guard and return value of lambda functions
+//	trace 0        stop tracing
+//	break `f       stop before calling the lambda function f
+//	break ⍳0       clear all breakpoints
+//
+// or with the commands /trace 1 and /break `f.
+// Errors from lambda functions carry the call stack, see ⎕DMX[`stack].
+//
+// Nested arrays are disabled by default:
+//
+//	nested 1       enable nested arrays: strands of arrays, ⊂ ⊃ ↑ ⊆ and ¨ work as in APL2
+//	nested 0       disable nested arrays
 package a
 
 import (
@@ -23,20 +45,29 @@ func Register(p *apl.Apl, name string) {
 		name = "a"
 	}
 	pkg := map[string]apl.Value{
-		"c": apl.ToFunction(cpus),
-		"g": apl.ToFunction(goroutines),
-		"h": apl.ToFunction(help),
-		"m": apl.ToFunction(Memstats),
-		"p": apl.ToFunction(printvar),
-		"q": apl.ToFunction(quit),
-		"t": apl.ToFunction(timer),
-		"v": apl.ToFunction(goversion),
+		"break":  apl.ToFunction(breakpoints),
+		"c":      apl.ToFunction(cpus),
+		"g":      apl.ToFunction(goroutines),
+		"h":      apl.ToFunction(help),
+		"load":   apl.ToFunction(load),
+		"m":      apl.ToFunction(Memstats),
+		"nested": apl.ToFunction(nested),
+		"p":      apl.ToFunction(printvar),
+		"q":      apl.ToFunction(quit),
+		"save":   apl.ToFunction(save),
+		"t":      apl.ToFunction(timer),
+		"trace":  apl.ToFunction(trace),
+		"v":      apl.ToFunction(goversion),
 	}
 	cmd := map[string]scan.Command{
-		"h": rw0("h"),
-		"p": toCommand(printCmd),
-		"q": rw0("q"),
-		"t": toCommand(timeCmd),
+		"break": rw1("break"),
+		"h":     rw0("h"),
+		"load":  rw1("load"),
+		"p":     toCommand(printCmd),
+		"q":     rw0("q"),
+		"save":  rw1("save"),
+		"t":     toCommand(timeCmd),
+		"trace": rw1("trace"),
 	}
 	p.AddCommands(cmd)
 	p.RegisterPackage(name, pkg)
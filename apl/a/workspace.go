@@ -0,0 +1,50 @@
+package a
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ktye/iv/apl"
+)
+
+// save writes the workspace to the file given as a string.
+// It returns the names of variables that could not be saved.
+func save(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	s, ok := R.(apl.String)
+	if ok == false {
+		return nil, fmt.Errorf("a save: argument must be a file name: %T", R)
+	}
+	f, err := os.Create(string(s))
+	if err != nil {
+		return nil, err
+	}
+	skipped, err := a.SaveWorkspace(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	if len(skipped) == 0 {
+		return apl.EmptyArray{}, nil
+	}
+	return apl.StringArray{Dims: []int{len(skipped)}, Strings: skipped}, nil
+}
+
+// load replaces the current workspace with the content of the file.
+func load(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	s, ok := R.(apl.String)
+	if ok == false {
+		return nil, fmt.Errorf("a load: argument must be a file name: %T", R)
+	}
+	f, err := os.Open(string(s))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := a.LoadWorkspace(f); err != nil {
+		return nil, err
+	}
+	return apl.EmptyArray{}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"io/ioutil"
 	"reflect"
+	"unicode/utf8"
 
 	"github.com/ktye/iv/apl/scan"
 )
@@ -12,30 +13,46 @@ import (
 // New starts a new interpreter.
 func New(w io.Writer) *Apl {
 	a := Apl{
-		stdout: w,
-		env:    newEnv(),
-		Origin: 1,
-		Format: Format{Fmt: make(map[reflect.Type]string)},
+		stdout:    w,
+		env:       newEnv(),
+		Origin:    1,
+		Tolerance: DefaultTolerance,
+		Format:    Format{Fmt: make(map[reflect.Type]string)},
 		//PP:         0,
 		//Fmt:        make(map[reflect.Type]string),
 		primitives: make(map[Primitive][]PrimitiveHandler),
 		operators:  make(map[string][]Operator),
 		symbols:    make(map[rune]string),
 		pkg:        make(map[string]*env),
+		towers:     make(map[string]func(*Apl, string) error),
+		numtypes:   make(map[string]Tower),
+		state:      &evalState{},
 	}
 	a.parser.a = &a
 	return &a
 }
 
+// Fork returns a copy of the interpreter to be used by a go routine.
+// It shares registered functions, packages, variables and the limits of the
+// current evaluation, but has it's own evaluation frame and parser.
+// Changes to ⎕IO, ⎕PP or ⎕CT in the fork do not affect the original.
+func (a *Apl) Fork() *Apl {
+	f := *a
+	f.parser = parser{a: &f}
+	f.frames = a.Stack()
+	return &f
+}
+
 // Apl stores the interpreter state.
 type Apl struct {
 	scan.Scanner
 	Format Format
 	parser
-	stdout io.Writer
-	stdimg ImageWriter
-	Tower  Tower
-	Origin int
+	stdout    io.Writer
+	stdimg    ImageWriter
+	Tower     Tower
+	Origin    int
+	Tolerance Tolerance
 	//PP         int
 	//Fmt        map[reflect.Type]string
 	env        *env
@@ -43,12 +60,25 @@ type Apl struct {
 	operators  map[string][]Operator
 	symbols    map[rune]string
 	pkg        map[string]*env
+	towers     map[string]func(*Apl, string) error
+	numtypes   map[string]Tower // towers that have been set by the names of their number types
 	scaninit   bool
+	Limits     Limits
+	Sandbox    *Sandbox
+	Debugger   *Debugger
+	Nested     bool // strands may contain arrays, see package nested
+	state      *evalState
+	frames     []Frame
+	options    []Object // variant options of the current function
+	pending    []Object // variant options for the next function call
 }
 
 type Format struct {
 	PP  int
 	Fmt map[reflect.Type]string
+	Box bool // box display, see box.go
+	PW  int  // page width, see page.go
+	PH  int  // page height
 }
 
 // LoadPkg loads a package from a file.
@@ -77,7 +107,7 @@ func (a *Apl) Parse(line string) (Program, error) {
 		return nil, err
 	}
 
-	p, err := a.parse(tokens)
+	p, err := a.parse(tokens, &source{lines: []string{line}})
 	if err != nil {
 		return nil, err
 	} else {
@@ -104,7 +134,12 @@ func (a *Apl) Scan(line string) ([]scan.Token, error) {
 		a.SetSymbols(m)
 		a.scaninit = true
 	}
-	return a.Scanner.Scan(line)
+	tokens, err := a.Scanner.Scan(line)
+	if e, ok := err.(scan.Error); ok {
+		_, n := utf8.DecodeRuneInString(line[e.Pos:])
+		return nil, Error{E: e.Err, Pos: Pos{Source: line, Start: e.Pos, End: e.Pos + n}}
+	}
+	return tokens, err
 }
 
 func (a *Apl) SetOutput(w io.Writer) {
@@ -40,7 +40,11 @@ func (ar array) Eval(a *Apl) (Value, error) {
 		if err != nil {
 			return nil, err
 		}
-		if a.isScalar(e) == false {
+		if _, ok := e.(Array); ok && a.Nested {
+			// In nested mode, arrays become items and an enclosed scalar contributes its item.
+			e = Disclose(e)
+			uni = false
+		} else if a.isScalar(e) == false {
 			return nil, fmt.Errorf("vector element must be scalar: %T", e)
 		}
 		if i == 0 {
@@ -207,6 +211,9 @@ type MixedArray struct {
 }
 
 func (v MixedArray) String(f Format) string {
+	if IsNested(v) && f.PP > -2 && f.Box == false {
+		return nestedString(f, v)
+	}
 	return ArrayString(f, v)
 }
 
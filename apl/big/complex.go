@@ -21,6 +21,25 @@ func (c Complex) Copy() apl.Value {
 	return Complex{re, im}
 }
 
+// MarshalText encodes real and imag part separated by J, see Float.MarshalText.
+func (c Complex) MarshalText() ([]byte, error) {
+	re, _ := Float{c.re}.MarshalText()
+	im, _ := Float{c.im}.MarshalText()
+	return []byte(string(re) + "J" + string(im)), nil
+}
+func (c *Complex) UnmarshalText(b []byte) error {
+	v := strings.Split(string(b), "J")
+	if len(v) != 2 {
+		return fmt.Errorf("cannot parse complex: %s", b)
+	}
+	var err error
+	if c.re, err = parsePrec(v[0]); err != nil {
+		return err
+	}
+	c.im, err = parsePrec(v[1])
+	return err
+}
+
 // String formats a complex as a string. The polar form is not supported.
 func (c Complex) String(f apl.Format) string {
 	// TODO parse MAGaDEG
@@ -102,6 +121,22 @@ func (c Complex) Equals(R apl.Value) (apl.Bool, bool) {
 	return apl.Bool(c.re.Cmp(z.re) == 0 && c.im.Cmp(z.im) == 0), true
 }
 
+// TolerantEquals compares with the comparison tolerance ⎕DCT
+// using the magnitude of the difference.
+func (c Complex) TolerantEquals(R apl.Value, t apl.Tolerance) (apl.Bool, bool) {
+	z := R.(Complex)
+	if c.re.Cmp(z.re) == 0 && c.im.Cmp(z.im) == 0 {
+		return true, true
+	}
+	d, _ := c.Sub2(z)
+	m := c.cpy().abs()
+	if az := z.cpy().abs(); az.Cmp(m) > 0 {
+		m = az
+	}
+	m.Mul(m, big.NewFloat(t.DCT))
+	return apl.Bool(d.(Complex).abs().Cmp(m) <= 0), true
+}
+
 func (c Complex) Add() (apl.Value, bool) {
 	z := c.cpy()
 	z.im = z.im.Neg(z.im)
@@ -21,6 +21,31 @@ func (f Float) Copy() apl.Value {
 	return Float{re}
 }
 
+// MarshalText encodes the float as "prec:value".
+// In contrast to big.Float, the precision is preserved.
+func (f Float) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d:%s", f.Float.Prec(), f.Float.Text('g', -1))), nil
+}
+func (f *Float) UnmarshalText(b []byte) error {
+	z, err := parsePrec(string(b))
+	f.Float = z
+	return err
+}
+
+// parsePrec parses a big.Float in the format "prec:value".
+func parsePrec(s string) (*big.Float, error) {
+	var prec uint
+	idx := strings.Index(s, ":")
+	if idx == -1 {
+		return nil, fmt.Errorf("cannot parse float: %s", s)
+	}
+	if _, err := fmt.Sscanf(s[:idx], "%d", &prec); err != nil {
+		return nil, fmt.Errorf("cannot parse float precision: %s", s)
+	}
+	z, _, err := big.NewFloat(0).SetPrec(prec).Parse(s[idx+1:], 10)
+	return z, err
+}
+
 func (f Float) String(af apl.Format) string {
 	format, minus := getformat(af, f)
 	if format == "" {
@@ -76,6 +101,30 @@ func (f Float) Less(R apl.Value) (apl.Bool, bool) {
 	return f.Float.Cmp(R.(Float).Float) < 0, true
 }
 
+// TolerantEquals compares with the comparison tolerance ⎕DCT.
+func (f Float) TolerantEquals(R apl.Value, t apl.Tolerance) (apl.Bool, bool) {
+	return apl.Bool(tolerantEqual(f.Float, R.(Float).Float, t.DCT)), true
+}
+
+// tolerantEqual returns true if |x-y| ≤ dct×(|x|⌈|y|).
+func tolerantEqual(x, y *big.Float, dct float64) bool {
+	if x.Cmp(y) == 0 {
+		return true
+	}
+	prec := x.Prec()
+	if y.Prec() > prec {
+		prec = y.Prec()
+	}
+	d := new(big.Float).SetPrec(prec).Sub(x, y)
+	d.Abs(d)
+	m := new(big.Float).Abs(x)
+	if ay := new(big.Float).Abs(y); ay.Cmp(m) > 0 {
+		m = ay
+	}
+	m = new(big.Float).SetPrec(prec).Mul(m, big.NewFloat(dct))
+	return d.Cmp(m) <= 0
+}
+
 func (f Float) Add() (apl.Value, bool) {
 	return f, true
 }
@@ -177,6 +226,22 @@ func (f Float) Floor() (apl.Value, bool) {
 	return Float{f.cpy().SetFloat64(math.Floor(z))}, true
 }
 
+// TolerantFloor returns ⌊f+1, if it is tolerantly equal to f, otherwise ⌊f.
+func (f Float) TolerantFloor(t apl.Tolerance) (apl.Value, bool) {
+	fl, _ := f.Floor()
+	n := fl.(Float).cpy()
+	n.Add(n, big.NewFloat(1))
+	if tolerantEqual(f.Float, n, t.DCT) {
+		return Float{n}, true
+	}
+	return fl, true
+}
+func (f Float) TolerantCeil(t apl.Tolerance) (apl.Value, bool) {
+	z, _ := f.Sub()
+	fl, _ := z.(Float).TolerantFloor(t)
+	return fl.(Float).Sub()
+}
+
 // TODO Trig
 
 // TODO Gcd
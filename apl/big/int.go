@@ -30,6 +30,13 @@ func (i Int) Copy() apl.Value {
 	return Int{r}
 }
 
+// UnmarshalText allocates the big.Int before decoding.
+// MarshalText is inherited.
+func (i *Int) UnmarshalText(b []byte) error {
+	i.Int = new(big.Int)
+	return i.Int.UnmarshalText(b)
+}
+
 func ParseInt(s string) (apl.Number, bool) {
 	s = strings.Replace(s, "¯", "-", -1)
 	i := new(big.Int)
@@ -22,6 +22,18 @@ func (r Rat) Copy() apl.Value {
 	t = t.Set(r.Rat)
 	return Rat{t}
 }
+// MarshalText encodes the rational number as "a/b".
+func (r Rat) MarshalText() ([]byte, error) {
+	return []byte(r.Rat.String()), nil
+}
+func (r *Rat) UnmarshalText(b []byte) error {
+	var ok bool
+	if r.Rat, ok = new(big.Rat).SetString(string(b)); ok == false {
+		return fmt.Errorf("cannot parse rational: %s", b)
+	}
+	return nil
+}
+
 func (r Rat) String(f apl.Format) string {
 	format, minus := getformat(f, r)
 	if format == "" {
@@ -17,6 +17,7 @@ func Register(a *apl.Apl, name string) {
 		name = "big"
 	}
 	a.RegisterPackage(name, pkg)
+	a.RegisterTower("big", setTowerName)
 }
 
 // SetBigTower sets the numerical tower to Int->Rat.
@@ -33,6 +34,7 @@ func SetBigTower(a *apl.Apl) {
 		Uptype: func(n apl.Number) (apl.Number, bool) { return n, false },
 	}
 	t := apl.Tower{
+		Name:    "big",
 		Numbers: m,
 		Import: func(n apl.Number) apl.Number {
 			if b, ok := n.(apl.Bool); ok {
@@ -66,6 +68,7 @@ func SetPreciseTower(a *apl.Apl, prec uint) {
 		Uptype: func(n apl.Number) (apl.Number, bool) { return n, false },
 	}
 	t := apl.Tower{
+		Name:    fmt.Sprintf("big %d", prec),
 		Numbers: m,
 		Import: func(n apl.Number) apl.Number {
 			if b, ok := n.(apl.Bool); ok {
@@ -107,6 +110,21 @@ func settower(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	return R, nil
 }
 
+// setTowerName sets the tower by the name used in a workspace:
+// "big" for Int->Rat and "big 256" for the precise tower with a precision of 256 bits.
+func setTowerName(a *apl.Apl, name string) error {
+	if name == "big" {
+		SetBigTower(a)
+		return nil
+	}
+	var prec uint
+	if _, err := fmt.Sscanf(name, "big %d", &prec); err != nil || prec < 2 {
+		return fmt.Errorf("unknown tower: %s", name)
+	}
+	SetPreciseTower(a, prec)
+	return nil
+}
+
 func getformat(f apl.Format, num apl.Value) (string, bool) {
 	if f.Fmt == nil {
 		return "", false
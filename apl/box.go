@@ -0,0 +1,285 @@
+package apl
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Box display is selected with ⎕PP←¯4 or with the format option ⍕⍠(`mode#"box").
+// Arrays, Lists, Dicts and Tables are drawn with a frame.
+// The top border shows the shape, the bottom border the type:
+//	┌2 3──┐
+//	│1 2 3│
+//	│4 5 6│
+//	└int──┘
+// Arrays of rank 2 and higher use the same tabwriter layout as ArrayString.
+// Values within Lists, Dicts and nested arrays have their own frames.
+// Scalars are not boxed.
+
+// boxed returns the box display of a value.
+func boxed(f Format, v Value) string {
+	f.Box = false
+	return strings.Join(boxValue(f, v), "\n")
+}
+
+// boxValue returns the lines of the box display of v.
+// The format f is used for scalars, it must not select the box display.
+func boxValue(f Format, v Value) []string {
+	switch x := v.(type) {
+	case Table:
+		keys := x.Keys()
+		lines := strings.Split(strings.TrimSuffix(x.String(f), "\n"), "\n")
+		return frame(lines, boxShape([]int{x.Rows, len(keys)}), "table")
+	case Object:
+		return frame(boxObject(f, x), strconv.Itoa(len(x.Keys())), boxType(v))
+	case List:
+		cells := make([][]string, len(x))
+		for i, e := range x {
+			cells[i] = boxItem(f, e)
+		}
+		return frame(hcat(cells), strconv.Itoa(len(x)), "list")
+	case Array:
+		return frame(boxArray(f, x), boxShape(x.Shape()), boxType(v))
+	}
+	return []string{v.String(f)}
+}
+
+// boxItem returns the lines for a value within a box.
+// Containers have their own frame, scalars are formatted as usual.
+func boxItem(f Format, v Value) []string {
+	if isContainer(v) {
+		return boxValue(f, v)
+	}
+	return strings.Split(v.String(f), "\n")
+}
+
+func isContainer(v Value) bool {
+	switch v.(type) {
+	case Array, Object:
+		return true
+	}
+	return false
+}
+
+// boxArray returns the content lines of an array.
+// Simple arrays are formatted by ArrayString, arrays that contain
+// containers are laid out as a grid of boxes.
+func boxArray(f Format, v Array) []string {
+	nested := false
+	for i := 0; i < v.Size(); i++ {
+		if isContainer(v.At(i)) {
+			nested = true
+			break
+		}
+	}
+	if nested == false {
+		return unindent(strings.Split(ArrayString(f, v), "\n"))
+	}
+	return gridPlanes(v, func(e Value) []string { return boxItem(f, e) }, false)
+}
+
+// nestedString formats a nested array with rules around each item.
+// It is the display of nested arrays, if the box display is not selected.
+//	┌───┬─────┐
+//	│1 2│3 4 5│
+//	└───┴─────┘
+// An enclosed scalar is a single box.
+func nestedString(f Format, v MixedArray) string {
+	lines := gridPlanes(v, func(e Value) []string { return unindent(strings.Split(e.String(f), "\n")) }, true)
+	return strings.Join(lines, "\n")
+}
+
+// gridPlanes lays out the items of an array as a grid.
+// Arrays with a rank larger than 2 are printed as a sequence of matrices
+// separated by an empty line.
+func gridPlanes(v Array, item func(Value) []string, ruled bool) []string {
+	shape := v.Shape()
+	rows, cols := 1, v.Size()
+	if len(shape) > 1 {
+		cols = shape[len(shape)-1]
+		rows = shape[len(shape)-2]
+	}
+	var lines []string
+	for n := 0; n < v.Size(); n += rows * cols {
+		if n > 0 {
+			lines = append(lines, "")
+		}
+		cells := make([][]string, rows*cols)
+		for i := range cells {
+			cells[i] = item(v.At(n + i))
+		}
+		lines = append(lines, grid(cells, rows, cols, ruled)...)
+	}
+	return lines
+}
+
+// boxObject returns the content lines of a dict or object.
+// Each key is followed by the box display of it's value.
+func boxObject(f Format, d Object) []string {
+	keys := d.Keys()
+	names := make([]string, len(keys))
+	w := 0
+	for i, k := range keys {
+		names[i] = k.String(f) + ":"
+		if n := utf8.RuneCountInString(names[i]); n > w {
+			w = n
+		}
+	}
+	var lines []string
+	for i, k := range keys {
+		for n, s := range boxItem(f, d.At(k)) {
+			name := ""
+			if n == 0 {
+				name = names[i]
+			}
+			lines = append(lines, pad(name, w)+" "+s)
+		}
+	}
+	return lines
+}
+
+// boxShape formats the shape annotation.
+func boxShape(shape []int) string {
+	s := make([]string, len(shape))
+	for i, n := range shape {
+		s[i] = strconv.Itoa(n)
+	}
+	return strings.Join(s, " ")
+}
+
+// boxType returns the type annotation of a value.
+// Arrays are annotated by the type of their elements, or mixed.
+func boxType(v Value) string {
+	name := func(v Value) string {
+		t := reflect.TypeOf(v)
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		return strings.ToLower(t.Name())
+	}
+	ar, ok := v.(Array)
+	if ok == false {
+		return name(v)
+	} else if ar.Size() == 0 {
+		return "empty"
+	}
+	t := name(ar.At(0))
+	for i := 1; i < ar.Size(); i++ {
+		if name(ar.At(i)) != t {
+			return "mixed"
+		}
+	}
+	return t
+}
+
+// frame draws a box around the lines with annotations in the top and bottom border.
+func frame(lines []string, top, bottom string) []string {
+	w := utf8.RuneCountInString(top)
+	if n := utf8.RuneCountInString(bottom); n > w {
+		w = n
+	}
+	for _, s := range lines {
+		if n := utf8.RuneCountInString(s); n > w {
+			w = n
+		}
+	}
+	border := func(l, s, r string) string {
+		return l + s + strings.Repeat("─", w-utf8.RuneCountInString(s)) + r
+	}
+	res := make([]string, 0, len(lines)+2)
+	res = append(res, border("┌", top, "┐"))
+	for _, s := range lines {
+		res = append(res, "│"+pad(s, w)+"│")
+	}
+	return append(res, border("└", bottom, "┘"))
+}
+
+// hcat joins blocks of lines horizontally separated by a space, aligned at the top.
+func hcat(blocks [][]string) []string {
+	return grid(blocks, 1, len(blocks), false)
+}
+
+// grid arranges blocks of lines in rows and columns separated by a space.
+// If ruled is true, the blocks are separated by rules and framed instead.
+func grid(cells [][]string, rows, cols int, ruled bool) []string {
+	widths := make([]int, cols)
+	heights := make([]int, rows)
+	for i, c := range cells {
+		if len(c) > heights[i/cols] {
+			heights[i/cols] = len(c)
+		}
+		for _, s := range c {
+			if n := utf8.RuneCountInString(s); n > widths[i%cols] {
+				widths[i%cols] = n
+			}
+		}
+	}
+	rule := func(l, m, r string) string {
+		v := make([]string, cols)
+		for c, w := range widths {
+			v[c] = strings.Repeat("─", w)
+		}
+		return l + strings.Join(v, m) + r
+	}
+	var lines []string
+	if ruled {
+		lines = append(lines, rule("┌", "┬", "┐"))
+	}
+	for r, h := range heights {
+		if ruled && r > 0 {
+			lines = append(lines, rule("├", "┼", "┤"))
+		}
+		for k := 0; k < h; k++ {
+			v := make([]string, cols)
+			for c := range v {
+				s := ""
+				if cell := cells[r*cols+c]; k < len(cell) {
+					s = cell[k]
+				}
+				v[c] = pad(s, widths[c])
+			}
+			if ruled {
+				lines = append(lines, "│"+strings.Join(v, "│")+"│")
+			} else {
+				lines = append(lines, strings.TrimRight(strings.Join(v, " "), " "))
+			}
+		}
+	}
+	if ruled {
+		lines = append(lines, rule("└", "┴", "┘"))
+	}
+	return lines
+}
+
+// unindent removes the leading spaces, that are common to all non-empty lines.
+// The tabwriter of ArrayString aligns to the right and indents rank 2 arrays.
+func unindent(lines []string) []string {
+	n := -1
+	for _, s := range lines {
+		if s == "" {
+			continue
+		}
+		if k := len(s) - len(strings.TrimLeft(s, " ")); n < 0 || k < n {
+			n = k
+		}
+	}
+	if n <= 0 {
+		return lines
+	}
+	for i, s := range lines {
+		if len(s) >= n {
+			lines[i] = s[n:]
+		}
+	}
+	return lines
+}
+
+// pad appends spaces to s up to the width w.
+func pad(s string, w int) string {
+	if n := utf8.RuneCountInString(s); n < w {
+		return s + strings.Repeat(" ", w-n)
+	}
+	return s
+}
@@ -44,12 +44,18 @@ func (c Channel) Close() {
 
 // scope return a channel and copies values from R[0].
 // It is called by scope assignment: ⎕←R.
+// Values are printed from a go routine with a fork of the interpreter.
 func (R Channel) Scope(a *Apl) Channel {
 	c := NewChannel()
+	done := a.Done()
+	a = a.Fork()
 	go func(r Channel) {
 		defer close(c[0])
 		for {
 			select {
+			case <-done:
+				close(r[1])
+				return
 			case _, ok := <-c[1]:
 				if ok == false {
 					close(r[1])
@@ -77,6 +83,7 @@ func (R Channel) Scope(a *Apl) Channel {
 // Apply returns a new channel.
 // It reads values from c[0], applies the f the each value and writes the result to return
 // returned channel.
+// The function is called from a go routine with a fork of the interpreter.
 // L (may be nil) is used as a left value for f.
 // If L is also a channel, a value is read each time, before applying f.
 // If filter is true, values are skipped if f returns an EmptyArray.
@@ -85,11 +92,19 @@ func (R Channel) Apply(a *Apl, f Function, L Value, filter bool) Channel {
 	l, lc := L.(Channel)
 
 	c := NewChannel()
+	done := a.Done()
+	a = a.Fork()
 	go func(r Channel) {
 		defer close(c[0])
 		var err error
 		for {
 			select {
+			case <-done:
+				close(r[1])
+				if lc {
+					close(l[1])
+				}
+				return
 			case _, ok := <-c[1]:
 				if ok == false {
 					close(r[1])
@@ -110,7 +125,7 @@ func (R Channel) Apply(a *Apl, f Function, L Value, filter bool) Channel {
 				}
 				v, err = f.Call(a, lv, v)
 				if err != nil {
-					c[0] <- Error{err}
+					c[0] <- Error{E: err}
 					close(r[1])
 					return
 				}
@@ -0,0 +1,169 @@
+package apl
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Frame is an entry of the call stack of lambda functions.
+// Name is the variable name, the lambda has been assigned to first, or λ for anonymous lambdas.
+// Guard is the index of the guarded expression that is evaluated, starting at 0.
+// L and R are the arguments ⍺ and ⍵, L is nil for a monadic call.
+//
+// The call stack is attached to every error returned from a lambda function.
+type Frame struct {
+	Name  string
+	Guard int
+	L, R  Value
+	body  guardList
+}
+
+// String formats the frame on a single line:
+//	f[1] ⍺:1 2 ⍵:3
+func (fr Frame) String(f Format) string {
+	return fmt.Sprintf("%s[%d] %s", fr.Name, fr.Guard, args(f, fr.L, fr.R))
+}
+
+// args formats the lambda arguments for the call stack and the trace.
+// Multi-line values are joined and long values are truncated.
+func args(f Format, L, R Value) string {
+	short := func(v Value) string {
+		s := strings.Replace(v.String(f), "\n", "⋄", -1)
+		if utf8.RuneCountInString(s) > 40 {
+			s = string([]rune(s)[:40]) + "…"
+		}
+		return s
+	}
+	s := "⍵:" + short(R)
+	if L != nil {
+		s = "⍺:" + short(L) + " " + s
+	}
+	return s
+}
+
+// Stack returns a copy of the current call stack.
+// The innermost call is the last frame.
+func (a *Apl) Stack() []Frame {
+	return append([]Frame(nil), a.frames...)
+}
+
+// StackTrace formats the call stack with one line for each frame, the innermost call first.
+func StackTrace(stack []Frame, f Format) string {
+	var b strings.Builder
+	for i := len(stack) - 1; i >= 0; i-- {
+		b.WriteString(stack[i].String(f))
+		b.WriteRune('\n')
+	}
+	return b.String()
+}
+
+// Debugger configures tracing and breakpoints of lambda functions.
+// It is disabled, if Apl.Debugger is nil.
+//
+// If Trace is not nil, each call of a lambda function, each guarded expression and
+// the return values are logged, indented by the call depth.
+//
+// Breakpoints contains names of lambda functions.
+// When such a lambda is called, Break is called with the interpreter in the environment
+// of the lambda function and the current call stack.
+// If it returns an error, the evaluation is stopped.
+// If Break is nil, a breakpoint stops with an error.
+type Debugger struct {
+	Trace       io.Writer
+	Breakpoints map[string]bool
+	Break       func(*Apl, []Frame) error
+}
+
+// frameName returns the name of the lambda function or lambda operator, that is called.
+func frameName(self Value) string {
+	switch f := self.(type) {
+	case *lambda:
+		if f.name != "" {
+			return f.name
+		}
+	case *lambdaDerived:
+		if f.op.name != "" {
+			return f.op.name
+		}
+	}
+	return "λ"
+}
+
+// tracing returns true, if the trace output is enabled.
+func (a *Apl) tracing() bool {
+	return a.Debugger != nil && a.Debugger.Trace != nil
+}
+
+// trace writes a line to the trace output indented by the call depth.
+func (a *Apl) trace(format string, v ...interface{}) {
+	if a.tracing() == false {
+		return
+	}
+	n := len(a.frames) - 1
+	if n < 0 {
+		n = 0
+	}
+	fmt.Fprintf(a.Debugger.Trace, strings.Repeat("  ", n)+format+"\n", v...)
+}
+
+// debugCall traces a lambda call and stops at a breakpoint.
+func (a *Apl) debugCall() error {
+	d := a.Debugger
+	if d == nil {
+		return nil
+	}
+	fr := a.frames[len(a.frames)-1]
+	a.trace("%s %s", fr.Name, args(a.Format, fr.L, fr.R))
+	if d.Breakpoints[fr.Name] == false {
+		return nil
+	}
+	if d.Break == nil {
+		return fmt.Errorf("breakpoint: %s", fr.Name)
+	}
+	return d.Break(a, a.Stack())
+}
+
+// step sets the guard index of the current frame and traces the guarded expression.
+func (a *Apl) step(g *guardExpr) {
+	if len(a.frames) == 0 {
+		return
+	}
+	fr := &a.frames[len(a.frames)-1]
+	for i := range fr.body {
+		if fr.body[i] == g {
+			fr.Guard = i
+		}
+	}
+	if a.tracing() == false {
+		return
+	} else if g.trap {
+		a.trace("  [%d] %s::", fr.Guard, g.cond.String(a.Format))
+	} else if g.cond == nil && g.e != nil {
+		a.trace("  [%d] %s", fr.Guard, g.e.String(a.Format))
+	}
+}
+
+// traceCond traces the result of a guard condition.
+func (a *Apl) traceCond(g *guardExpr, b Bool) {
+	if len(a.frames) > 0 && a.tracing() {
+		a.trace("  [%d] %s: %s", a.frames[len(a.frames)-1].Guard, g.cond.String(a.Format), b.String(a.Format))
+	}
+}
+
+// stackError attaches the current call stack to an error.
+// If the error has a call stack already, it is returned unchanged.
+func (a *Apl) stackError(err error) error {
+	if isLimit(err) {
+		return err
+	}
+	e, ok := err.(Error)
+	if ok == false {
+		e = Error{E: err}
+	}
+	if e.Stack == nil {
+		e.Stack = a.Stack()
+	}
+	return e
+}
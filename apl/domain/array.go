@@ -122,6 +122,26 @@ func (v vector) String(f apl.Format) string {
 	return name + " " + v.child.String(f)
 }
 
+// IsMatrix accepts only arrays with rank 2.
+func IsMatrix(child SingleDomain) SingleDomain {
+	return matrix{child}
+}
+
+type matrix struct{ child SingleDomain }
+
+func (m matrix) To(a *apl.Apl, V apl.Value) (apl.Value, bool) {
+	if ar, ok := V.(apl.Array); ok && len(ar.Shape()) == 2 {
+		return propagate(a, V, m.child)
+	}
+	return V, false
+}
+func (m matrix) String(f apl.Format) string {
+	if m.child == nil {
+		return "matrix"
+	}
+	return "matrix " + m.child.String(f)
+}
+
 func ToBoolArray(child SingleDomain) SingleDomain {
 	return boolarray{child, true}
 }
@@ -0,0 +1,25 @@
+package domain
+
+import "github.com/ktye/iv/apl"
+
+// Nested accepts values only if nested arrays are enabled, see apl.Apl.Nested.
+func Nested(child SingleDomain) SingleDomain {
+	return nested{child}
+}
+
+type nested struct {
+	child SingleDomain
+}
+
+func (v nested) To(a *apl.Apl, V apl.Value) (apl.Value, bool) {
+	if a.Nested == false {
+		return V, false
+	}
+	return propagate(a, V, v.child)
+}
+func (v nested) String(f apl.Format) string {
+	if v.child == nil {
+		return "nested"
+	}
+	return "nested " + v.child.String(f)
+}
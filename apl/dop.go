@@ -0,0 +1,146 @@
+package apl
+
+import (
+	"fmt"
+)
+
+// lambdaOp is a lambda expression that refers to ⍺⍺ or ⍵⍵.
+// It is a user defined operator, also known as dop in Dyalog.
+//
+// ⍺⍺ is the left and ⍵⍵ the right operand, which may be functions or arrays.
+// If the body refers to ⍵⍵ the operator is dyadic, otherwise it is monadic.
+//	twice←{⍺⍺ ⍺⍺ ⍵}
+//	×⍨twice 3
+// Applied to it's operands it returns a derived function, in which ∇ refers to the
+// derived function itself.
+//
+// The parser has to know if an operand is an array or a function.
+// This is only known when the operator is applied, so the body is parsed for
+// each combination of operand classes in advance.
+//
+// Like lambda functions, lambda operators capture the environment in which they are evaluated.
+type lambdaOp struct {
+	body   [4]guardList // indexed by operand classes: 1 for array ⍺⍺, 2 for array ⍵⍵
+	err    [4]error
+	dyadic bool
+	env    *env
+	src    string
+	name   string
+}
+
+func (op *lambdaOp) String(f Format) string {
+	for _, b := range op.body {
+		if b != nil {
+			return fmt.Sprintf("{%s}", b.String(f))
+		}
+	}
+	return "{}"
+}
+func (op *lambdaOp) Copy() Value { return op }
+
+func (op *lambdaOp) Eval(a *Apl) (Value, error) {
+	if op.env != nil {
+		return op, nil
+	}
+	c := *op
+	c.env = a.env
+	return &c, nil
+}
+
+// class returns the parse class of the operator.
+func (op *lambdaOp) class() class {
+	if op.dyadic {
+		return conjunction
+	}
+	return adverb
+}
+
+// derive binds the operator to it's operands and returns the derived function.
+func (op *lambdaOp) derive(lo, ro Value) (Function, error) {
+	k := 0
+	if _, ok := lo.(Function); ok == false {
+		k |= 1
+	}
+	if _, ok := ro.(Function); op.dyadic && ok == false {
+		k |= 2
+	}
+	if op.err[k] != nil {
+		return nil, op.err[k]
+	}
+	return &lambdaDerived{op: op, body: op.body[k], lo: lo, ro: ro}, nil
+}
+
+// lambdaDerived is the function derived from a lambda operator.
+type lambdaDerived struct {
+	op     *lambdaOp
+	body   guardList
+	lo, ro Value
+}
+
+func (d *lambdaDerived) String(f Format) string {
+	if d.op.dyadic {
+		return fmt.Sprintf("(%s %s %s)", d.lo.String(f), d.op.String(f), d.ro.String(f))
+	}
+	return fmt.Sprintf("(%s %s)", d.lo.String(f), d.op.String(f))
+}
+func (d *lambdaDerived) Copy() Value { return d }
+
+func (d *lambdaDerived) Call(a *Apl, l, r Value) (Value, error) {
+	defer a.restoreOptions(a.takeOptions())
+	vars := map[string]Value{"⍺⍺": d.lo}
+	if d.op.dyadic {
+		vars["⍵⍵"] = d.ro
+	}
+	return d.body.call(a, d.op.env, d, l, r, vars)
+}
+
+// callLambdaOp evaluates the operands of a derived expression with a lambda operator
+// and calls the derived function.
+func (d *derived) callLambdaOp(a *Apl, l, r Value) (Value, error) {
+	v, err := d.dop.Eval(a)
+	if err != nil {
+		return nil, err
+	}
+	op, ok := v.(*lambdaOp)
+	if ok == false {
+		return nil, fmt.Errorf("%s is not an operator: %T", d.dop.String(a.Format), v)
+	}
+	if d.lo == nil {
+		return nil, fmt.Errorf("operator %s has no left operand", d.dop.String(a.Format))
+	}
+	lo, err := d.lo.Eval(a)
+	if err != nil {
+		return nil, err
+	}
+	var ro Value
+	if op.dyadic {
+		if d.ro == nil {
+			return nil, fmt.Errorf("operator %s has no right operand", d.dop.String(a.Format))
+		}
+		ro, err = d.ro.Eval(a)
+		if err != nil {
+			return nil, err
+		}
+	}
+	f, err := op.derive(lo, ro)
+	if err != nil {
+		return nil, err
+	}
+	return f.Call(a, l, r)
+}
+
+// opVar is the name of a variable that contains a lambda operator.
+// It evaluates to the stored value.
+type opVar string
+
+func (o opVar) String(f Format) string {
+	return string(o)
+}
+
+func (o opVar) Eval(a *Apl) (Value, error) {
+	v := a.Lookup(string(o))
+	if v == nil {
+		return nil, fmt.Errorf("operator %s does not exist", string(o))
+	}
+	return v, nil
+}
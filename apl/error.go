@@ -1,16 +1,154 @@
 package apl
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Error carries an error value.
 // It is used by go routines to signal errors.
-// To send err over Channel c, use: c[0]<-Error{e}
+// To send err over Channel c, use: c[0]<-Error{E: e}
+//
+// Error is also a go error, that can be trapped in a lambda function with an error guard:
+//	{0::⎕DMX ⋄ 1÷`x}0
+// Within the error guard, ⎕DMX contains the Error value.
+// It is an object with the keys code, message, expr and stack.
+//
+// The code is 0 for errors raised by primitives, or the code given to ⎕signal.
 type Error struct {
-	E error
+	E     error
+	Code  int     // error number
+	Expr  string  // failing expression
+	Pos   Pos     // source position of the failing function
+	Stack []Frame // call stack of lambda functions
 }
 
-func (e Error) String(f Format) string {
+func (e Error) Error() string {
 	if e.E == nil {
 		return "<nil error>"
 	}
 	return e.E.Error()
 }
+
+func (e Error) String(f Format) string {
+	return e.Error()
+}
 func (e Error) Copy() Value { return e }
+
+func (e Error) Keys() []Value {
+	return []Value{String("code"), String("message"), String("expr"), String("stack")}
+}
+
+func (e Error) At(key Value) Value {
+	s, ok := key.(String)
+	if ok == false {
+		return nil
+	}
+	switch s {
+	case "code":
+		return Int(e.Code)
+	case "message":
+		return String(e.Error())
+	case "expr":
+		return String(e.Expr)
+	case "stack":
+		return e.stack()
+	}
+	return nil
+}
+
+// stack returns the call stack as a vector of strings, the innermost call last.
+func (e Error) stack() StringArray {
+	v := make([]string, len(e.Stack))
+	for i, fr := range e.Stack {
+		v[i] = fr.String(Format{})
+	}
+	return StringArray{Dims: []int{len(v)}, Strings: v}
+}
+
+func (e Error) Set(key, v Value) error {
+	return fmt.Errorf("error values are read-only")
+}
+
+// exprError attaches the failing expression and it's position to an error.
+// If err already carries an expression, it is returned unchanged.
+// Errors from exceeding limits are not modified.
+func exprError(err error, x expr, p Pos, f Format) error {
+	if isLimit(err) {
+		return err
+	}
+	if e, ok := err.(Error); ok {
+		if e.Expr == "" {
+			e.Expr = x.String(f)
+			if e.Pos.valid() == false {
+				e.Pos = p
+			}
+		}
+		return e
+	}
+	return Error{E: err, Expr: x.String(f), Pos: p}
+}
+
+// trap tests if the error matches one of the error codes.
+// Code 0 matches any error.
+func (e Error) trap(codes Value) (bool, error) {
+	match := func(v Value) (bool, error) {
+		n, ok := v.(Number)
+		if ok == false {
+			return false, fmt.Errorf("error guard: codes must be numbers: %T", v)
+		}
+		c, ok := n.ToIndex()
+		if ok == false {
+			return false, fmt.Errorf("error guard: codes must be integers")
+		}
+		return c == 0 || c == e.Code, nil
+	}
+	if ar, ok := codes.(Array); ok {
+		for i := 0; i < ar.Size(); i++ {
+			if ok, err := match(ar.At(i)); err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+	return match(codes)
+}
+
+// signal is the system function ⎕signal.
+// It raises an error with a code and an optional message.
+//
+//	"message" ⎕signal 11
+//
+// Called with an Error value, it raises the error again:
+//
+//	⎕signal ⎕DMX
+type signal struct{}
+
+func (s signal) String(f Format) string { return "⎕signal" }
+func (s signal) Copy() Value            { return s }
+
+func (s signal) Call(a *Apl, L, R Value) (Value, error) {
+	if e, ok := R.(Error); ok && L == nil {
+		return nil, e
+	}
+	n, ok := R.(Number)
+	if ok == false {
+		return nil, fmt.Errorf("⎕signal: right argument must be an error code: %T", R)
+	}
+	code, ok := n.ToIndex()
+	if ok == false || code < 1 {
+		return nil, fmt.Errorf("⎕signal: error code must be a positive integer: %s", R.String(a.Format))
+	}
+	msg := fmt.Sprintf("error %d", code)
+	if L != nil {
+		switch v := L.(type) {
+		case String:
+			msg = string(v)
+		case StringArray:
+			msg = strings.Join(v.Strings, "")
+		default:
+			return nil, fmt.Errorf("⎕signal: left argument must be a string: %T", L)
+		}
+	}
+	return nil, Error{E: fmt.Errorf("%s", msg), Code: code}
+}
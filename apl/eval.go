@@ -2,6 +2,7 @@ package apl
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"runtime/debug"
@@ -13,10 +14,22 @@ type Program []expr
 
 // Eval executes an apl program.
 // It can be called in a loop for every line of input.
-func (a *Apl) Eval(p Program) (err error) {
+// The Limits apply to each call as for EvalContext,
+// unless it is called during a running evaluation.
+func (a *Apl) Eval(p Program) error {
+	if a.state.ctx == nil {
+		return a.EvalContext(context.Background(), p)
+	}
+	return a.eval(p)
+}
+
+func (a *Apl) eval(p Program) (err error) {
+	depth, options, pending := len(a.frames), a.options, a.pending
 	defer func() {
 		if r := recover(); r != nil {
-			err = fmt.Errorf("panic: %s\n%s", r, string(debug.Stack()))
+			err = fmt.Errorf("panic: %s\n%s%s", r, StackTrace(a.frames, a.Format), string(debug.Stack()))
+			a.frames = a.frames[:depth]
+			a.options, a.pending = options, pending
 		}
 	}()
 	write := func(val Value) {
@@ -43,6 +56,10 @@ func (a *Apl) Eval(p Program) (err error) {
 			case Channel:
 				i := 0
 				for e := range v[0] {
+					if err := a.Check(); err != nil {
+						go v.Close()
+						return err
+					}
 					if i == 0 {
 						i++
 						if _, ok := e.(Image); ok && a.stdimg != nil {
@@ -95,6 +112,7 @@ func (a *Apl) EvalFile(r io.Reader, file string) (err error) {
 	ok := true
 	var p Program
 	b := NewLineBuffer(a)
+	b.file = file
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line++
@@ -127,8 +145,21 @@ type fileError struct {
 	err  error
 }
 
+// Error returns the error message prefixed by the file name and line number.
+// If the error has a source position, the line is that of the position and
+// the source line is appended with a caret below the failing expression.
 func (f fileError) Error() string {
-	return fmt.Sprintf("%s:%d: %s", f.file, f.line, f.err.Error())
+	file, line, caret := f.file, f.line, ""
+	if e, ok := f.err.(Error); ok && e.Pos.valid() {
+		if e.Pos.File != "" {
+			file, line = e.Pos.File, e.Pos.Line
+		}
+		caret = "\n" + e.Pos.Caret()
+	}
+	if e, ok := f.err.(Error); ok && len(e.Stack) > 0 {
+		caret += "\n" + strings.TrimSuffix(StackTrace(e.Stack, Format{}), "\n")
+	}
+	return fmt.Sprintf("%s:%d: %s%s", file, line, f.err.Error(), caret)
 }
 
 type expr interface {
@@ -25,10 +25,12 @@ import (
 //  -3:  arrays formatted in a single line compatible with matlab
 //  -8:  integers formatted as octal numbers with 0 prefix
 // -16:  integers formatted as hexadecimal numbers with 0x prefix, floats with %b (-123456p-78)
+// PP == -4 selects the box display and keeps the current precision, any other number switches it off.
 func (a *Apl) SetPP(R Value) error {
 	if _, ok := R.(EmptyArray); ok {
 		a.Format.PP = 0
 		a.Format.Fmt = make(map[reflect.Type]string)
+		a.Format.Box = false
 		return nil
 	} else if d, ok := R.(Object); ok {
 		keys := d.Keys()
@@ -41,8 +43,12 @@ func (a *Apl) SetPP(R Value) error {
 			}
 		}
 	} else if n, ok := R.(Number); ok {
-		if i, ok := n.ToIndex(); ok {
+		if i, ok := n.ToIndex(); ok && i == -4 {
+			a.Format.Box = true
+			return nil
+		} else if ok {
 			a.Format.PP = i
+			a.Format.Box = false
 			return nil
 		}
 	}
@@ -54,8 +60,12 @@ func (a *Apl) SetPP(R Value) error {
 // Each dimension is terminated by k newlines, where k is the dimension index.
 // For PP==-2, it uses a single line json notation with nested brackets and
 // for PP==-3, it formats in a single line matlab syntax (rank <= 2).
+// If the box display is selected, the array is drawn within a frame.
+// The output is limited by the page size ⎕PW and ⎕PH, see page.go.
 func ArrayString(f Format, v Array) string {
-	if f.PP == -2 {
+	if f.Box {
+		return boxed(f, v)
+	} else if f.PP == -2 {
 		return jsonArray(f, v)
 	} else if f.PP == -3 {
 		return matArray(f, v)
@@ -63,6 +73,10 @@ func ArrayString(f Format, v Array) string {
 	shape := v.Shape()
 	if len(shape) == 0 {
 		return ""
+	} else if f.paged() && len(shape) == 1 {
+		return pageVector(f.page(), v)
+	} else if f.paged() {
+		return pageArray(f.page(), v)
 	} else if len(shape) == 1 {
 		s := make([]string, shape[0])
 		for i := 0; i < shape[0]; i++ {
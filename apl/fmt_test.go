@@ -3,6 +3,7 @@ package apl
 import (
 	"fmt"
 	"io"
+	"math"
 	"strings"
 	"testing"
 )
@@ -103,3 +104,87 @@ func TestScanRankArray(t *testing.T) {
 	next:
 	}
 }
+
+func TestBox(t *testing.T) {
+	d := Dict{K: []Value{String("a"), String("b")}, M: map[Value]Value{
+		String("a"): Int(1),
+		String("b"): IntArray{Dims: []int{2}, Ints: []int{2, 3}},
+	}}
+	testCases := []struct {
+		v   Value
+		exp string
+	}{
+		{Int(1), "1"},
+		{IntArray{Dims: []int{2, 3}, Ints: []int{1, 2, 3, 4, 5, 6}}, "┌2 3──┐\n│1 2 3│\n│4 5 6│\n└int──┘"},
+		{StringArray{Dims: []int{2}, Strings: []string{"a", "b"}}, "┌2─────┐\n│a b   │\n└string┘"},
+		{List{Int(1), IntArray{Dims: []int{2}, Ints: []int{2, 3}}}, "┌2──────┐\n│1 ┌2──┐│\n│  │2 3││\n│  └int┘│\n└list───┘"},
+		{&d, "┌2───────┐\n│a: 1    │\n│b: ┌2──┐│\n│   │2 3││\n│   └int┘│\n└dict────┘"},
+		{MixedArray{Dims: []int{2}, Values: []Value{Int(1), String("x")}}, "┌2────┐\n│1 x  │\n└mixed┘"},
+	}
+	for i, tc := range testCases {
+		if got := tc.v.String(Format{Box: true}); got != tc.exp {
+			t.Fatalf("#%d: expected:\n%s\ngot:\n%s", i, tc.exp, got)
+		}
+	}
+}
+
+func TestPage(t *testing.T) {
+	iota := func(shape ...int) IntArray {
+		v := IntArray{Dims: shape, Ints: make([]int, Prod(shape))}
+		for i := range v.Ints {
+			v.Ints[i] = i + 1
+		}
+		return v
+	}
+	testCases := []struct {
+		v      Value
+		pw, ph int
+		exp    string
+	}{
+		{iota(12), 0, 0, "1 2 3 4 5 6 7 8 9 10 11 12"},
+		{iota(12), 20, 0, "1 2 3 4 5 6 7 8 9 10\n      11 12"},
+		{iota(100), 20, 2, "1 2 3 4 5 6 7 8 9 10\n… ⍴100"},
+		{iota(2, 6), 12, 0, " 1 2 3  4  5\n 7 8 9 10 11\n\n  6\n 12"},
+		{iota(2, 2, 2), 0, 3, " 1 2\n 3 4\n… ⍴2 2 2"},
+		{iota(100, 100), 12, 3, "   1   2 …\n 101 102 …\n… ⍴100 100"},
+	}
+	for i, tc := range testCases {
+		if got := tc.v.String(Format{PW: tc.pw, PH: tc.ph}); got != tc.exp {
+			t.Fatalf("#%d: expected:\n%s\ngot:\n%s", i, tc.exp, got)
+		}
+	}
+}
+
+func TestNumericPicture(t *testing.T) {
+	testCases := []struct {
+		pic string
+		x   float64
+		exp string
+	}{
+		{"0", 3.7, "4"},
+		{"0.00", 3.14159, "3.14"},
+		{"#,##0.00", 1234567.891, "1,234,567.89"},
+		{"#,##0", -1500, "-1,500"},
+		{"000", 7, "007"},
+		{"0.0##", 1.5, "1.5"},
+		{"0.0##", 1.23456, "1.235"},
+		{".00", 0.5, ".50"},
+		{"$#,##0.00", -0.001, "$0.00"},
+		{"+0.0", 1, "+1.0"},
+		{"+0.0", -1, "-1.0"},
+		{"(#,##0)", -1500, "(1,500)"},
+		{"(#,##0)", 1500, " 1,500 "},
+		{"0.0 kg", 12.25, "12.3 kg"},
+		{"0", 2.5, "3"},
+		{"0", -2.5, "-3"},
+		{"0.00", math.NaN(), "NaN"},
+		{"0.00", math.Inf(1), "∞"},
+		{"#,##0 kg", math.Inf(-1), "-∞"},
+		{"none", 1, "none"},
+	}
+	for i, tc := range testCases {
+		if got := numericPicture(tc.pic, tc.x); got != tc.exp {
+			t.Fatalf("#%d: %q %v: expected %q got %q", i, tc.pic, tc.x, tc.exp, got)
+		}
+	}
+}
@@ -12,11 +12,17 @@ type Function interface {
 }
 
 // EnvCall calls a function in a new environment.
+// A lambda function is called with the new environment inserted
+// between it's closure and the local variables.
 func (a *Apl) EnvCall(f Function, L, R Value, vars map[string]Value) (Value, error) {
 	e := env{
 		vars:   vars,
 		parent: a.env,
 	}
+	if λ, ok := f.(*lambda); ok && λ.env != nil {
+		e.parent = λ.env
+		f = &lambda{body: λ.body, env: &e, src: λ.src, name: λ.name}
+	}
 	save := a.env
 	a.env = &e
 	defer func() { a.env = save }()
@@ -28,6 +34,7 @@ type function struct {
 	Function
 	left, right expr
 	selection   bool
+	pos         Pos // position of the function in the source
 }
 
 // Eval calls the function with it's surrounding arugments.
@@ -66,7 +73,19 @@ func (f *function) Eval(a *Apl) (Value, error) {
 			return p.Select(a, l, r)
 		}
 	}
-	return f.Function.Call(a, l, r)
+	if err := a.Check(); err != nil {
+		return nil, err
+	}
+	v, err := f.Function.Call(a, l, r)
+	if err != nil {
+		return nil, exprError(err, f, f.pos, a.Format)
+	}
+	if ar, ok := v.(Array); ok {
+		if err := a.CheckSize(ar.Size()); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
 }
 
 func (f *function) String(af Format) string {
@@ -123,11 +142,15 @@ func (p Primitive) Copy() Value { return p }
 // they are tested in reverse registration order, until the first one takes the
 // responsibility.
 func (p Primitive) Call(a *Apl, L, R Value) (Value, error) {
+	defer a.restoreOptions(a.takeOptions())
 	if handles := a.primitives[p]; handles == nil {
 		return nil, fmt.Errorf("primitive function %s does not exist", p)
 	} else {
 		for _, h := range handles {
 			if l, r, ok := h.To(a, L, R); ok {
+				if err := a.Sandbox.checkOverload(string(p), h.Doc(), false); err != nil {
+					return nil, err
+				}
 				return h.Call(a, l, r)
 			}
 		}
@@ -147,6 +170,9 @@ func (p Primitive) Select(a *Apl, L, R Value) (IntArray, error) {
 	} else {
 		for _, h := range handles {
 			if l, r, ok := h.To(a, L, R); ok {
+				if err := a.Sandbox.checkOverload(string(p), h.Doc(), false); err != nil {
+					return IntArray{}, err
+				}
 				return h.Select(a, l, r)
 			}
 		}
@@ -196,6 +222,7 @@ func (h pHandler) Select(*Apl, Value, Value) (IntArray, error) {
 type ToFunction func(*Apl, Value, Value) (Value, error)
 
 func (f ToFunction) Call(a *Apl, L, R Value) (Value, error) {
+	defer a.restoreOptions(a.takeOptions())
 	return f(a, L, R)
 }
 
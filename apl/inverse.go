@@ -0,0 +1,114 @@
+package apl
+
+import "fmt"
+
+// Inverter is implemented by functions and primitive handlers, that have an inverse.
+//
+// CallInverse applies the inverse function to R.
+// The inverse of a dyadic function is taken with respect to the right argument,
+// the left argument is kept:
+//	L f⍣¯1 L f R  ←→  R
+type Inverter interface {
+	CallInverse(a *Apl, L, R Value) (Value, error)
+}
+
+// CallInverse applies the inverse of the function f to R.
+// It returns an error, if f does not have an inverse.
+func CallInverse(a *Apl, f Function, L, R Value) (Value, error) {
+	if inv, ok := f.(Inverter); ok {
+		return inv.CallInverse(a, L, R)
+	}
+	if v, ok := f.(Value); ok {
+		return nil, fmt.Errorf("function has no inverse: %s", v.String(a.Format))
+	}
+	return nil, fmt.Errorf("function has no inverse")
+}
+
+// CallInverse dispatches the inverse call to the primitive handler, that accepts L and R.
+func (p Primitive) CallInverse(a *Apl, L, R Value) (Value, error) {
+	for _, h := range a.primitives[p] {
+		if l, r, ok := h.To(a, L, R); ok {
+			if err := a.Sandbox.checkOverload(string(p), h.Doc(), false); err != nil {
+				return nil, err
+			}
+			if inv, ok := h.(Inverter); ok {
+				return inv.CallInverse(a, l, r)
+			}
+			break
+		}
+	}
+	return nil, fmt.Errorf("primitive %s has no inverse", p)
+}
+
+// CallInverse calls the inverse of the function stored in the variable.
+func (f fnVar) CallInverse(a *Apl, l, r Value) (Value, error) {
+	fn, ok := a.Lookup(string(f)).(Function)
+	if ok == false {
+		return nil, fmt.Errorf("value in function variable is not a function: %s", string(f))
+	}
+	return CallInverse(a, fn, l, r)
+}
+
+// CallInverse calls the inverse of the function, that is derived by the operator.
+func (d *derived) CallInverse(a *Apl, l, r Value) (Value, error) {
+	if d.dop != nil {
+		return nil, fmt.Errorf("lambda operators have no inverse")
+	}
+	f, l, err := d.derive(a, l)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := f.(Inverter); ok == false {
+		return nil, fmt.Errorf("function has no inverse: %s", d.String(a.Format))
+	}
+	return CallInverse(a, f, l, r)
+}
+
+// CallInverse calls the inverse of an atop or a fork.
+func (t train) CallInverse(a *Apl, L, R Value) (Value, error) {
+	f, err := t.split(a)
+	if err != nil {
+		return nil, err
+	}
+	return CallInverse(a, f, L, R)
+}
+
+// CallInverse inverts an atop:
+//	(g h)⍣¯1 R    ←→  h⍣¯1 g⍣¯1 R
+//	L (g h)⍣¯1 R  ←→  L h⍣¯1 g⍣¯1 R
+func (t atop) CallInverse(a *Apl, L, R Value) (Value, error) {
+	g, ok := t[0].(Function)
+	if ok == false {
+		return nil, fmt.Errorf("atop: expected function g: %T", t[0])
+	}
+	h, ok := t[1].(Function)
+	if ok == false {
+		return nil, fmt.Errorf("atop: expected function h: %T", t[1])
+	}
+	v, err := CallInverse(a, g, nil, R)
+	if err != nil {
+		return nil, err
+	}
+	return CallInverse(a, h, L, v)
+}
+
+// CallInverse inverts a monadic fork with an array as the left tine:
+//	(A g h)⍣¯1 R  ←→  h⍣¯1 A g⍣¯1 R
+func (fk fork) CallInverse(a *Apl, L, R Value) (Value, error) {
+	if _, ok := fk[0].(Function); ok || L != nil {
+		return nil, fmt.Errorf("fork: only a monadic fork with an array on the left has an inverse")
+	}
+	g, ok := fk[1].(Function)
+	if ok == false {
+		return nil, fmt.Errorf("fork: expected function g: %T", fk[1])
+	}
+	h, ok := fk[2].(Function)
+	if ok == false {
+		return nil, fmt.Errorf("fork: expected function h: %T", fk[2])
+	}
+	v, err := CallInverse(a, g, fk[0], R)
+	if err != nil {
+		return nil, err
+	}
+	return CallInverse(a, h, nil, v)
+}
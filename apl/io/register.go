@@ -1,6 +1,7 @@
 // Package io provides input and output streams.
 //
-// Linking it into APL leads to an unsafe system.
+// Linking it into APL leads to an unsafe system,
+// unless it is restricted by apl.Sandbox.
 // See README.md
 package io
 
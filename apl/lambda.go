@@ -3,19 +3,59 @@ package apl
 import (
 	"fmt"
 	"strings"
+	"sync"
 )
 
 // Env is the environment of the current lambda function.
 // It contains local variables and a pointer to the parent environment.
+//
+// Environments may be shared by go routines of a forked interpreter.
+// Variables are accessed with get and set.
 type env struct {
 	parent *env
 	vars   map[string]Value
+	mu     sync.RWMutex
+}
+
+func (e *env) get(name string) (Value, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	v, ok := e.vars[name]
+	return v, ok
+}
+
+func (e *env) set(name string, v Value) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.vars[name] = v
+}
+
+// names returns the names of all variables in the environment.
+func (e *env) names() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	l := make([]string, 0, len(e.vars))
+	for n := range e.vars {
+		l = append(l, n)
+	}
+	return l
 }
 
 // lambda is a function expression in braces {...}.
 // It is also known under the term dynamic function or dfn.
+//
+// Lambdas are lexically scoped.
+// Evaluating the expression returns a closure, which captures the current environment.
+// It is the parent environment of each call.
+//
+// The source is kept to store the lambda in a workspace.
+// The name is set, when the lambda is assigned to a variable the first time.
+// It is used in the call stack.
 type lambda struct {
 	body guardList
+	env  *env
+	src  string
+	name string
 }
 
 func (λ *lambda) String(f Format) string {
@@ -27,30 +67,77 @@ func (λ *lambda) String(f Format) string {
 func (λ *lambda) Copy() Value { return λ }
 
 func (λ *lambda) Eval(a *Apl) (Value, error) {
-	return λ, nil
+	if λ.env != nil {
+		return λ, nil
+	}
+	return &lambda{body: λ.body, env: a.env, src: λ.src, name: λ.name}, nil
 }
 
 func (λ *lambda) Call(a *Apl, l, r Value) (Value, error) {
-	if λ.body == nil {
+	defer a.restoreOptions(a.takeOptions())
+	return λ.body.call(a, λ.env, λ, l, r, nil)
+}
+
+// call evaluates the body of a lambda function in a new environment
+// with the given parent.
+// The value of ∇ is set to self, the arguments to ⍺ and ⍵.
+// Vars contains additional local variables, such as the operands of a lambda operator.
+func (body guardList) call(a *Apl, parent *env, self, l, r Value, vars map[string]Value) (Value, error) {
+	if body == nil {
 		return EmptyArray{}, nil
 	}
+	if parent == nil {
+		parent = a.env
+	}
+	leave, err := a.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	// The frame is not popped by a deferred function.
+	// It remains on the stack, if the evaluation panics.
+	a.frames = append(a.frames, Frame{Name: frameName(self), body: body})
+	n := len(a.frames)
+	v, err := body.callFrame(a, parent, self, l, r, vars)
+	if err != nil {
+		err = a.stackError(err)
+	} else if a.tracing() {
+		a.trace("%s → %s", a.frames[n-1].Name, strings.Replace(v.String(a.Format), "\n", "⋄", -1))
+	}
+	a.frames = a.frames[:n-1]
+	return v, err
+}
 
-	e := env{
+// callFrame evaluates the body of a lambda function with a frame on the call stack.
+func (body guardList) callFrame(a *Apl, parent *env, self, l, r Value, vars map[string]Value) (Value, error) {
+	e := &env{
 		vars:   make(map[string]Value),
-		parent: a.env,
+		parent: parent,
 	}
 	save := a.env
-	a.env = &e
+	a.env = e
 	defer func() { a.env = save }()
 
-	e.vars["∇"] = λ
+	for k, v := range vars {
+		e.vars[k] = v
+	}
+	e.vars["∇"] = self
 tail:
-	e.vars["⍺"] = l
-	e.vars["⍵"] = r
+	e.set("⍺", l)
+	e.set("⍵", r)
+	fr := &a.frames[len(a.frames)-1]
+	fr.L, fr.R, fr.Guard = l, r, 0
+	if err := a.debugCall(); err != nil {
+		return nil, err
+	}
 
-	if v, err := λ.body.Eval(a); err != nil {
+	if v, err := body.Eval(a); err != nil {
 		return nil, err
 	} else if t, ok := v.(*tail); ok {
+		if err := a.Check(); err != nil {
+			return nil, err
+		}
 		r, err = t.right.Eval(a)
 		if err != nil {
 			return nil, err
@@ -95,6 +182,10 @@ func (l guardList) Eval(a *Apl) (Value, error) {
 	}
 	var ret Value = EmptyArray{}
 	for i, g := range l {
+		a.step(g)
+		if g.trap {
+			return l.trap(a, i)
+		}
 		isa := isAssignment(g.e)
 		if g.cond == nil && i < len(l)-1 && isa == false {
 			return nil, fmt.Errorf("λ contains non-reachable code")
@@ -120,16 +211,58 @@ func (l guardList) Eval(a *Apl) (Value, error) {
 	return ret, nil
 }
 
+// trap evaluates the guardList following the error guard at index i.
+// If it fails with an error, that matches the codes of the guard,
+// the guarded expression is evaluated instead.
+// The error value is stored in the local variable ⎕DMX.
+//
+// A tail call within the scope of an error guard is evaluated as a normal call,
+// to trap it's errors.
+func (l guardList) trap(a *Apl, i int) (Value, error) {
+	codes, err := l[i].cond.Eval(a)
+	if err != nil {
+		return nil, err
+	}
+	v, err := l[i+1:].Eval(a)
+	if t, ok := v.(*tail); ok && err == nil {
+		var L, R Value
+		R, err = t.right.Eval(a)
+		if err == nil && t.left != nil {
+			L, err = t.left.Eval(a)
+		}
+		if err == nil {
+			v, err = self{}.Call(a, L, R)
+		}
+	}
+	if err == nil {
+		return v, nil
+	} else if isLimit(err) {
+		return nil, err
+	}
+	e := a.stackError(err).(Error)
+	if match, err := e.trap(codes); err != nil {
+		return nil, err
+	} else if match == false {
+		return nil, e
+	}
+	a.env.set("⎕DMX", e)
+	return l[i].e.Eval(a)
+}
+
 // guardExpr contains a guarded expression.
 // It's expressions is evaluated if the condition returns true or is nil.
+// If trap is set, it is an error guard and cond contains the error codes.
 type guardExpr struct {
 	cond expr
 	e    expr
+	trap bool
 }
 
 func (g *guardExpr) String(f Format) string {
 	if g.cond == nil {
 		return g.e.String(f)
+	} else if g.trap {
+		return g.cond.String(f) + "::" + g.e.String(f)
 	} else {
 		return g.cond.String(f) + ":" + g.e.String(f)
 	}
@@ -162,6 +295,7 @@ func (g *guardExpr) Eval(a *Apl) (Value, error) {
 		return nil, fmt.Errorf("λ condition does not return a bool: %s", b.String(a.Format))
 	}
 
+	a.traceCond(g, b)
 	if b == false {
 		return nil, nil
 	} else {
@@ -185,15 +319,15 @@ func (s self) Call(a *Apl, L, R Value) (Value, error) {
 	if a.env.parent == nil {
 		return nil, fmt.Errorf("cannot call ∇ outside lambda")
 	}
-	v, ok := a.env.vars["∇"]
+	v, ok := a.env.get("∇")
 	if ok == false {
 		return nil, fmt.Errorf("∇ has not been registered") // should not happen
 	}
-	λ, ok := v.(*lambda)
+	fn, ok := v.(Function)
 	if ok == false {
-		return nil, fmt.Errorf("∇ is not a lambda") // should not happen
+		return nil, fmt.Errorf("∇ is not a function") // should not happen
 	}
-	return λ.Call(a, L, R)
+	return fn.Call(a, L, R)
 }
 
 // Tail contains the left and right expression for a tail call.
@@ -0,0 +1,164 @@
+package apl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Limits restricts the resources used by an evaluation.
+// A zero value disables the limit.
+//
+// Time and Steps apply to a single call of Eval or EvalContext.
+// A step is a function call from an expression or within a loop of an operator.
+// Expressions that are evaluated outside of Eval, e.g. by EvalProgram, are not limited.
+//
+// Size is checked before allocating by primitives that build large arrays
+// from small arguments, such as ⍳ ⍴ , / and ∘.
+// Other results are checked after the call.
+type Limits struct {
+	Time  time.Duration // wall time
+	Steps int64         // number of evaluation steps
+	Depth int           // recursion depth of lambda functions
+	Size  int           // number of elements of an array
+	Power int           // iterations of the power operator with a function as right operand
+}
+
+// Errors returned by EvalContext, if a limit is exceeded.
+var (
+	ErrTimeLimit  = errors.New("time limit exceeded")
+	ErrStepLimit  = errors.New("step limit exceeded")
+	ErrDepthLimit = errors.New("recursion depth limit exceeded")
+	ErrSizeLimit  = errors.New("array size limit exceeded")
+)
+
+// evalState is the state of a running evaluation that is checked against the Limits.
+type evalState struct {
+	ctx      context.Context
+	deadline time.Time
+	steps    int64
+	depth    int
+	mu       sync.Mutex
+	err      error // first limit error or cancellation
+}
+
+// EvalContext evaluates a program like Eval.
+// It returns the context's error, if the context is cancelled before the evaluation is complete.
+// It also returns an ErrTimeLimit or ErrStepLimit, if the program exceeds the interpreter's Limits.
+func (a *Apl) EvalContext(ctx context.Context, p Program) error {
+	save := a.state
+	s := &evalState{ctx: ctx, depth: save.depth}
+	if a.Limits.Time > 0 {
+		s.deadline = time.Now().Add(a.Limits.Time)
+	}
+	a.state = s
+	defer func() { a.state = save }()
+
+	err := a.eval(p)
+	if e := s.error(); e != nil {
+		return e
+	}
+	return err
+}
+
+// Check returns an error, if the evaluation has been cancelled or exceeds it's time or step limit.
+// It counts as a single step and should be called within loops of long running functions.
+func (a *Apl) Check() error {
+	s := a.state
+	if s.ctx == nil {
+		return nil
+	}
+	if err := s.error(); err != nil {
+		return err
+	}
+	if n := atomic.AddInt64(&s.steps, 1); a.Limits.Steps > 0 && n > a.Limits.Steps {
+		return s.fail(ErrStepLimit)
+	}
+	if s.deadline.IsZero() == false && time.Now().After(s.deadline) {
+		return s.fail(ErrTimeLimit)
+	}
+	select {
+	case <-s.ctx.Done():
+		return s.fail(s.ctx.Err())
+	default:
+	}
+	return nil
+}
+
+// CheckSize returns ErrSizeLimit if an array of size n is not allowed.
+// It should be called before allocating large arrays.
+func (a *Apl) CheckSize(n int) error {
+	if a.Limits.Size > 0 && n > a.Limits.Size {
+		return a.state.fail(ErrSizeLimit)
+	}
+	return nil
+}
+
+// Done returns a channel that is closed, when the evaluation is cancelled.
+// It returns nil, if the evaluation cannot be cancelled.
+// Go routines serving channels should select on it.
+func (a *Apl) Done() <-chan struct{} {
+	if a.state.ctx == nil {
+		return nil
+	}
+	return a.state.ctx.Done()
+}
+
+// PowerLimit returns the maximal number of iterations of the power operator.
+func (a *Apl) PowerLimit() int {
+	if a.Limits.Power > 0 {
+		return a.Limits.Power
+	}
+	return 1000
+}
+
+// enter increases the recursion depth of lambda calls.
+// If it does not return an error, the returned function must be called when the call returns.
+func (a *Apl) enter() (func(), error) {
+	s := a.state
+	s.mu.Lock()
+	s.depth++
+	d := s.depth
+	s.mu.Unlock()
+	leave := func() {
+		s.mu.Lock()
+		s.depth--
+		s.mu.Unlock()
+	}
+	if a.Limits.Depth > 0 && d > a.Limits.Depth {
+		leave()
+		return nil, s.fail(ErrDepthLimit)
+	}
+	if err := a.Check(); err != nil {
+		leave()
+		return nil, err
+	}
+	return leave, nil
+}
+
+// fail records the first error that stops the evaluation.
+func (s *evalState) fail(err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+	return err
+}
+
+func (s *evalState) error() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// isLimit returns true, if err stops the evaluation and cannot be trapped.
+func isLimit(err error) bool {
+	switch err {
+	case ErrTimeLimit, ErrStepLimit, ErrDepthLimit, ErrSizeLimit, context.Canceled, context.DeadlineExceeded:
+		return true
+	}
+	return false
+}
@@ -10,13 +10,17 @@ import (
 type List []Value
 
 func (l List) String(f Format) string {
-	if f.PP == -2 {
+	if f.Box {
+		return boxed(f, l)
+	} else if f.PP == -2 {
 		return l.jsonString(f)
 	}
 	var buf strings.Builder
 	buf.WriteRune('(')
 	for i := range l {
-		buf.WriteString(l[i].String(f))
+		if l[i] != nil { // empty element: (;)
+			buf.WriteString(l[i].String(f))
+		}
 		buf.WriteRune(';')
 	}
 	buf.WriteRune(')')
@@ -124,7 +128,9 @@ func (l list) String(f Format) string {
 	var buf strings.Builder
 	buf.WriteRune('(')
 	for i := range l {
-		buf.WriteString(l[i].String(f))
+		if l[i] != nil { // empty element: (;)
+			buf.WriteString(l[i].String(f))
+		}
 		buf.WriteRune(';')
 	}
 	buf.WriteRune(')')
@@ -26,10 +26,16 @@ func (a *Apl) ParseLines(lines string) (Program, error) {
 }
 
 // LineBuffer buffers multiline statements for lambda functions.
+// It numbers the lines it has seen, starting at 1, and keeps the lines of the
+// current statement for error positions.
 type LineBuffer struct {
 	a      *Apl
 	tokens []scan.Token
 	level  int
+	file   string
+	line   int
+	first  int
+	lines  []string
 }
 
 func NewLineBuffer(a *Apl) *LineBuffer {
@@ -42,14 +48,30 @@ func (b *LineBuffer) Add(line string) (bool, error) {
 	if b.a == nil {
 		return false, fmt.Errorf("linebuffer is not initialized (no APL)")
 	}
+	b.line++
 	tokens, err := b.a.Scan(line)
 	if err != nil {
 		b.reset()
+		if e, ok := err.(Error); ok {
+			e.Pos.File, e.Pos.Line = b.file, b.line
+			err = e
+		}
 		return false, err
 	}
+	if len(b.tokens) == 0 {
+		if len(tokens) == 0 {
+			return false, nil
+		}
+		b.first = b.line
+		b.lines = b.lines[:0]
+	}
+	b.lines = append(b.lines, line)
 	if len(tokens) == 0 {
 		return false, nil
 	}
+	for i := range tokens {
+		tokens[i].Line = b.line
+	}
 
 	// Join with diamonds. Ommit the diamond if the last token is LeftBrace
 	// or the next token is a RightBrace.
@@ -85,7 +107,8 @@ func (b *LineBuffer) Add(line string) (bool, error) {
 // Lines must be pushed to the buffer with Add and Parse should only be called if Add returned true.
 func (b *LineBuffer) Parse() (Program, error) {
 	defer b.reset()
-	return b.a.parse(b.tokens)
+	src := &source{file: b.file, first: b.first, lines: b.lines}
+	return b.a.parse(b.tokens, src)
 }
 
 func (b *LineBuffer) Len() int {
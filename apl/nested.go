@@ -0,0 +1,49 @@
+package apl
+
+// Nested arrays are an opt-in feature, see Apl.Nested and package nested.
+//
+// A nested array is a MixedArray that holds arrays as items.
+// An enclosed array is a MixedArray of rank 0 with a single item.
+// Simple scalars are not enclosed:
+//	⊂1 2 3    MixedArray{Dims: []int{}, Values: []Value{IntArray{...}}}
+//	(1 2)(3 4)  MixedArray{Dims: []int{2}, Values: []Value{IntArray{...}, IntArray{...}}}
+
+// Enclose returns v as an enclosed scalar, if it is an array.
+// Simple scalars are returned unchanged.
+func Enclose(v Value) Value {
+	if _, ok := v.(Array); ok {
+		return MixedArray{Dims: []int{}, Values: []Value{v}}
+	}
+	return v
+}
+
+// Disclose returns the item of an enclosed scalar.
+// All other values are returned unchanged.
+func Disclose(v Value) Value {
+	if IsEnclosed(v) {
+		return v.(MixedArray).Values[0]
+	}
+	return v
+}
+
+// IsEnclosed returns true, if v is an enclosed scalar.
+func IsEnclosed(v Value) bool {
+	m, ok := v.(MixedArray)
+	return ok && len(m.Dims) == 0 && len(m.Values) == 1
+}
+
+// IsNested returns true, if the array is enclosed or contains arrays as items.
+func IsNested(v Value) bool {
+	m, ok := v.(MixedArray)
+	if ok == false {
+		return false
+	} else if IsEnclosed(m) {
+		return true
+	}
+	for _, e := range m.Values {
+		if _, ok := e.(Array); ok {
+			return true
+		}
+	}
+	return false
+}
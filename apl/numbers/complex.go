@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"math/cmplx"
+	"strconv"
 	"strings"
 
 	"github.com/ktye/iv/apl"
@@ -64,6 +65,29 @@ func (c Complex) String(f apl.Format) string {
 }
 func (z Complex) Copy() apl.Value { return z }
 
+// MarshalText encodes real and imag part with full precision separated by J.
+func (z Complex) MarshalText() ([]byte, error) {
+	re := strconv.FormatFloat(real(z), 'g', -1, 64)
+	im := strconv.FormatFloat(imag(z), 'g', -1, 64)
+	return []byte(re + "J" + im), nil
+}
+func (z *Complex) UnmarshalText(b []byte) error {
+	v := strings.Split(string(b), "J")
+	if len(v) != 2 {
+		return fmt.Errorf("cannot parse complex: %s", b)
+	}
+	re, err := strconv.ParseFloat(v[0], 64)
+	if err != nil {
+		return err
+	}
+	im, err := strconv.ParseFloat(v[1], 64)
+	if err != nil {
+		return err
+	}
+	*z = Complex(complex(re, im))
+	return nil
+}
+
 // ParseComplex parses a Complex from a string.
 // The number may be given as MAGNITUDEaANGLE with the angle in degree,
 // or as realJimag or REALjIMAG.
@@ -196,6 +220,18 @@ func (c Complex) Log2(R apl.Value) (apl.Value, bool) {
 	return Complex(r) / Complex(l), true
 }
 
+// TolerantEquals compares with the comparison tolerance ⎕CT
+// using the magnitude of the difference.
+func (c Complex) TolerantEquals(R apl.Value, t apl.Tolerance) (apl.Bool, bool) {
+	r := R.(Complex)
+	if c == r {
+		return true, true
+	}
+	d := cmplx.Abs(complex128(c - r))
+	m := math.Max(cmplx.Abs(complex128(c)), cmplx.Abs(complex128(r)))
+	return apl.Bool(d <= t.CT*m), true
+}
+
 func (c Complex) Abs() (apl.Value, bool) {
 	// This is a downtype. It only works, if the tower includes Float.
 	return Float(cmplx.Abs(complex128(c))), true
@@ -37,6 +37,17 @@ func (n Float) String(f apl.Format) string {
 }
 func (f Float) Copy() apl.Value { return f }
 
+// MarshalText encodes the float with full precision.
+// It is used to store the number in a workspace.
+func (f Float) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatFloat(float64(f), 'g', -1, 64)), nil
+}
+func (f *Float) UnmarshalText(b []byte) error {
+	v, err := strconv.ParseFloat(string(b), 64)
+	*f = Float(v)
+	return err
+}
+
 // ParseFloat parses a Float. It replaces ¯ with -, then uses ParseFloat.
 // A trailing . is stripped, so that "2." is parsed as a float.
 func ParseFloat(s string) (apl.Number, bool) {
@@ -64,6 +75,11 @@ func (f Float) Less(R apl.Value) (apl.Bool, bool) {
 	return apl.Bool(f < R.(Float)), true
 }
 
+// TolerantEquals compares with the comparison tolerance ⎕CT.
+func (f Float) TolerantEquals(R apl.Value, t apl.Tolerance) (apl.Bool, bool) {
+	return apl.Bool(t.Equal(float64(f), float64(R.(Float)))), true
+}
+
 func (f Float) Add() (apl.Value, bool) {
 	return f, true
 }
@@ -145,6 +161,15 @@ func (f Float) Ceil() (apl.Value, bool) {
 	return Float(math.Ceil(float64(f))), true
 }
 
+// TolerantFloor and TolerantCeil round to the nearest integer,
+// if it is tolerantly equal to f.
+func (f Float) TolerantFloor(t apl.Tolerance) (apl.Value, bool) {
+	return Float(t.Floor(float64(f))), true
+}
+func (f Float) TolerantCeil(t apl.Tolerance) (apl.Value, bool) {
+	return Float(t.Ceil(float64(f))), true
+}
+
 func (f Float) Gamma() (apl.Value, bool) {
 	y := Float(math.Gamma(float64(f) + 1))
 	if e, ok := isException(y); ok {
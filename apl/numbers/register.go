@@ -14,6 +14,10 @@ func Register(a *apl.Apl) {
 	if err := a.SetTower(newTower()); err != nil {
 		panic(err)
 	}
+	a.RegisterTower("numbers", func(a *apl.Apl, _ string) error {
+		Register(a)
+		return nil
+	})
 }
 
 func newTower() apl.Tower {
@@ -41,6 +45,7 @@ func newTower() apl.Tower {
 		Uptype: func(n apl.Number) (apl.Number, bool) { return n, false },
 	}
 	t := apl.Tower{
+		Name:    "numbers",
 		Numbers: m,
 		Import: func(n apl.Number) apl.Number {
 			if b, ok := n.(apl.Bool); ok {
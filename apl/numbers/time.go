@@ -71,6 +71,29 @@ func (t Time) String(f apl.Format) string {
 }
 func (t Time) Copy() apl.Value { return t }
 
+// MarshalText encodes the time in RFC 3339 format with nanoseconds.
+// Times before year 0, which are negative durations, are encoded as unix seconds
+// and nanoseconds: "sec.nsec".
+func (t Time) MarshalText() ([]byte, error) {
+	if b, err := time.Time(t).MarshalText(); err == nil {
+		return b, nil
+	}
+	return []byte(fmt.Sprintf("%d.%09d", time.Time(t).Unix(), time.Time(t).Nanosecond())), nil
+}
+func (t *Time) UnmarshalText(b []byte) error {
+	var tt time.Time
+	if err := tt.UnmarshalText(b); err == nil {
+		*t = Time(tt)
+		return nil
+	}
+	var sec, nsec int64
+	if _, err := fmt.Sscanf(string(b), "%d.%d", &sec, &nsec); err != nil {
+		return fmt.Errorf("cannot parse time: %s", b)
+	}
+	*t = Time(time.Unix(sec, nsec).UTC())
+	return nil
+}
+
 func (t Time) ToIndex() (int, bool) {
 	return 0, false
 }
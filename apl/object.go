@@ -75,7 +75,9 @@ func (d *Dict) Set(key Value, v Value) error {
 }
 
 func (d *Dict) String(f Format) string {
-	if f.PP == -2 {
+	if f.Box {
+		return boxed(f, d)
+	} else if f.PP == -2 {
 		return d.jsonString(f)
 	} else if f.PP == -3 {
 		return d.matString(f)
@@ -36,6 +36,7 @@ type derived struct {
 	lo  expr                                       // left operand
 	ro  expr                                       // right operand
 	sel func(*Apl, Value, Value) (IntArray, error) // selection function for reduce and scan
+	dop expr                                       // lambda operator or it's variable, instead of op
 }
 
 func (d *derived) Eval(a *Apl) (Value, error) {
@@ -45,8 +46,12 @@ func (d *derived) Eval(a *Apl) (Value, error) {
 func (d *derived) String(f Format) string {
 	left := ""
 	right := ""
+	op := d.op
+	if d.dop != nil {
+		op = d.dop.String(f)
+	}
 	if d.lo == nil && d.ro == nil {
-		return d.op
+		return op
 	}
 	if d.lo != nil {
 		left = d.lo.String(f) + " "
@@ -54,7 +59,7 @@ func (d *derived) String(f Format) string {
 	if d.ro != nil {
 		right = " " + d.ro.String(f)
 	}
-	return "(" + left + d.op + right + ")"
+	return "(" + left + op + right + ")"
 }
 func (d *derived) Copy() Value { return d }
 
@@ -65,9 +70,24 @@ func (d *derived) Copy() Value { return d }
 // registration order until a handler accepts to build a derived function, which
 // is then called with l and r.
 func (d *derived) Call(a *Apl, l, r Value) (Value, error) {
+	defer a.restoreOptions(a.takeOptions())
+	if d.dop != nil {
+		return d.callLambdaOp(a, l, r)
+	}
+	f, l, err := d.derive(a, l)
+	if err != nil {
+		return nil, err
+	}
+	return f.Call(a, l, r)
+}
+
+// derive evaluates the operands and returns the function derived by the first operator handler,
+// that accepts them.
+// For modified assignment, l is replaced.
+func (d *derived) derive(a *Apl, l Value) (Function, Value, error) {
 	ops, ok := a.operators[d.op]
 	if ok == false || len(ops) == 0 || ops[0] == nil {
-		return nil, fmt.Errorf("operator %s does not exist", d.op)
+		return nil, nil, fmt.Errorf("operator %s does not exist", d.op)
 	}
 
 	// Evaluate the operands.
@@ -76,7 +96,7 @@ func (d *derived) Call(a *Apl, l, r Value) (Value, error) {
 	if ops[0].DyadicOp() { // All registerd operators have the same arity.
 		ro, err = d.ro.Eval(a)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -87,43 +107,49 @@ func (d *derived) Call(a *Apl, l, r Value) (Value, error) {
 		if l == nil {
 			lo, err = evalAssign(a, d.lo, nil)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		} else {
 			as, ok := l.(assignment)
 			if ok == false {
-				return nil, fmt.Errorf("modified assignment: expected assignment target expr on the left: %T", l)
+				return nil, nil, fmt.Errorf("modified assignment: expected assignment target expr on the left: %T", l)
 			}
 			var f Function
 			if d.lo != nil {
 				if pf, ok := d.lo.(Function); ok {
 					f = pf
 				} else {
-					return nil, fmt.Errorf("modifier is not a function: %T", d.lo)
+					return nil, nil, fmt.Errorf("modifier is not a function: %T", d.lo)
 				}
 			}
 			lo, err = evalAssign(a, as, f)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			l = nil
 		}
 	} else {
 		lo, err = d.lo.Eval(a)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	for _, op := range ops {
 		if LO, RO, ok := op.To(a, lo, ro); ok {
-			return op.Derived(a, LO, RO).Call(a, l, r)
+			if err := a.Sandbox.checkOverload(d.op, op.Doc(), true); err != nil {
+				return nil, nil, err
+			}
+			return op.Derived(a, LO, RO), l, nil
 		}
 	}
-	return nil, fmt.Errorf("cannot handle operator %T %s %T", lo, d.op, ro)
+	return nil, nil, fmt.Errorf("cannot handle operator %T %s %T", lo, d.op, ro)
 }
 
 func (d *derived) Select(a *Apl, L, R Value) (Value, error) {
+	if d.dop != nil {
+		return nil, fmt.Errorf("lambda operators cannot be used in selective assignments")
+	}
 	ops, ok := a.operators[d.op]
 	if ok == false || len(ops) == 0 || ops[0] == nil {
 		return nil, fmt.Errorf("operator %s does not exist", d.op)
@@ -149,6 +175,9 @@ func (d *derived) Select(a *Apl, L, R Value) (Value, error) {
 
 	for _, op := range ops {
 		if LO, RO, ok := op.To(a, LO, RO); ok {
+			if err := a.Sandbox.checkOverload(d.op, op.Doc(), true); err != nil {
+				return nil, err
+			}
 			return op.Select(a, L, LO, RO, R)
 		}
 	}
@@ -179,6 +179,9 @@ func outer(a *apl.Apl, L, R apl.Value, f, g apl.Function) (apl.Value, error) {
 	shape := make([]int, 0, len(ls)+len(rs))
 	shape = append(shape, apl.CopyShape(al)...)
 	shape = append(shape, apl.CopyShape(ar)...)
+	if err := a.CheckSize(apl.Prod(shape)); err != nil {
+		return nil, err
+	}
 	res := apl.NewMixed(shape)
 
 	lc, lidx := apl.NewIdxConverter(ls)
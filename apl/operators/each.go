@@ -22,6 +22,11 @@ func init() {
 	})
 }
 
+// each applies the function to each element of R, or to pairs of elements of L and R.
+// The results must be scalars, unless nested arrays are enabled.
+// In nested mode, each item of a nested array is passed as the argument,
+// and array results become items of the nested result:
+//	{⍳⍵}¨1 2 3
 func each(a *apl.Apl, LO, RO apl.Value) apl.Function {
 	f := LO.(apl.Function)
 	derived := func(a *apl.Apl, l, r apl.Value) (apl.Value, error) {
@@ -42,6 +47,14 @@ func each1(a *apl.Apl, R apl.Value, f apl.Function) (apl.Value, error) {
 		return eachChannel(a, nil, c, f)
 	}
 
+	if a.Nested && apl.IsEnclosed(R) {
+		v, err := f.Call(a, nil, apl.Disclose(R))
+		if err != nil {
+			return nil, err
+		}
+		return apl.Enclose(v), nil
+	}
+
 	ar, ok := R.(apl.Array)
 	if ok {
 		if ar.Size() == 0 {
@@ -57,11 +70,14 @@ func each1(a *apl.Apl, R apl.Value, f apl.Function) (apl.Value, error) {
 
 	res := apl.NewMixed(apl.CopyShape(ar))
 	for i := range res.Values {
+		if err := a.Check(); err != nil {
+			return nil, err
+		}
 		v, err := f.Call(a, nil, ar.At(i))
 		if err != nil {
 			return nil, err
 		}
-		if _, ok := v.(apl.Array); ok {
+		if _, ok := v.(apl.Array); ok && a.Nested == false {
 			return nil, fmt.Errorf("each: result must be a scalar")
 		}
 		res.Values[i] = v.Copy()
@@ -72,6 +88,9 @@ func each1(a *apl.Apl, R apl.Value, f apl.Function) (apl.Value, error) {
 func eachList(a *apl.Apl, l apl.List, f apl.Function) (apl.Value, error) {
 	res := make(apl.List, len(l))
 	for i := range res {
+		if err := a.Check(); err != nil {
+			return nil, err
+		}
 		v, err := f.Call(a, nil, l[i])
 		if err != nil {
 			return nil, err
@@ -127,8 +146,21 @@ func each2(a *apl.Apl, L, R apl.Value, f apl.Function) (apl.Value, error) {
 	al, lok := L.(apl.Array)
 	var rs, ls []int
 
+	if a.Nested {
+		// Enclosed scalars are extended like simple scalars.
+		if apl.IsEnclosed(R) {
+			R, rok = apl.Disclose(R), false
+		}
+		if apl.IsEnclosed(L) {
+			L, lok = apl.Disclose(L), false
+		}
+	}
 	if rok == false && lok == false {
-		return f.Call(a, L, R)
+		v, err := f.Call(a, L, R)
+		if err != nil || a.Nested == false {
+			return v, err
+		}
+		return apl.Enclose(v), nil
 	}
 	if rok == true && ar.Size() == 0 {
 		return apl.EmptyArray{}, nil // TODO fill function
@@ -175,11 +207,14 @@ func each2(a *apl.Apl, L, R apl.Value, f apl.Function) (apl.Value, error) {
 		if lok == true {
 			lv = al.At(i)
 		}
+		if err := a.Check(); err != nil {
+			return nil, err
+		}
 		v, err := f.Call(a, lv, rv)
 		if err != nil {
 			return nil, err
 		}
-		if _, ok := v.(apl.Array); ok {
+		if _, ok := v.(apl.Array); ok && a.Nested == false {
 			return nil, fmt.Errorf("each: result must be a scalar")
 		}
 		res.Values[i] = v.Copy()
@@ -210,6 +245,9 @@ func eachList2(a *apl.Apl, L, R apl.Value, f apl.Function) (apl.Value, error) {
 		if rok {
 			rv = r[i]
 		}
+		if err := a.Check(); err != nil {
+			return nil, err
+		}
 		v, err := f.Call(a, lv, rv)
 		if err != nil {
 			return nil, err
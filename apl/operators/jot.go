@@ -47,5 +47,54 @@ func compose(a *apl.Apl, f, g apl.Value) apl.Function {
 		}
 		return nil, fmt.Errorf("compose: cannot handle %T %T ∘ %T %T", L, f, g, R)
 	}
-	return function(derived)
+	inverse := func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		fn, isfunc := f.(apl.Function)
+		gn, isgunc := g.(apl.Function)
+		if isfunc && isgunc {
+			// Form I and IV: g⍣¯1 L f⍣¯1 R
+			v, err := apl.CallInverse(a, fn, L, R)
+			if err != nil {
+				return nil, err
+			}
+			return apl.CallInverse(a, gn, nil, v)
+		} else if isgunc && L == nil {
+			// Form II: A g⍣¯1 R
+			return apl.CallInverse(a, gn, f, R)
+		} else if p, ok := f.(apl.Primitive); ok && isfunc && L == nil {
+			// Form III: (f∘X)⍣¯1 R, only for arithmetic.
+			if v, ok, err := rightCurryInverse(a, p, g, R); ok {
+				return v, err
+			}
+		}
+		return nil, fmt.Errorf("compose: cannot invert %T ∘ %T", f, g)
+	}
+	return invertible{function(derived), function(inverse)}
+}
+
+// rightCurryInverse solves Y f X ←→ R for Y, which is the inverse of (f∘X) R.
+// It returns false, if f is not supported.
+//	(*∘X)⍣¯1 R ←→ R*÷X
+//	(⍟∘X)⍣¯1 R ←→ X*÷R
+func rightCurryInverse(a *apl.Apl, f apl.Primitive, X, R apl.Value) (apl.Value, bool, error) {
+	if inv, ok := map[apl.Primitive]apl.Primitive{"+": "-", "-": "+", "×": "÷", "÷": "×"}[f]; ok {
+		v, err := inv.Call(a, R, X)
+		return v, true, err
+	}
+	switch f {
+	case "*":
+		rx, err := apl.Primitive("÷").Call(a, nil, X)
+		if err != nil {
+			return nil, true, err
+		}
+		v, err := apl.Primitive("*").Call(a, R, rx)
+		return v, true, err
+	case "⍟":
+		rr, err := apl.Primitive("÷").Call(a, nil, R)
+		if err != nil {
+			return nil, true, err
+		}
+		v, err := apl.Primitive("*").Call(a, X, rr)
+		return v, true, err
+	}
+	return nil, false, nil
 }
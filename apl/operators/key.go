@@ -0,0 +1,403 @@
+package operators
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ktye/iv/apl"
+	. "github.com/ktye/iv/apl/domain"
+)
+
+func init() {
+	register(operator{
+		symbol:  "⌸",
+		Domain:  MonadicOp(Function(nil)),
+		doc:     "key, group by",
+		derived: key,
+	})
+}
+
+// key groups the major cells of an array by their unique values.
+// Monadic: f is called for each unique major cell of R with the cell as ⍺
+// and the indexes where it occurs as ⍵:
+//	{⍺,≢⍵}⌸3 1 3 3
+// Dyadic: the keys are the major cells of L, and ⍵ contains the corresponding major cells of R:
+//	1 2 1 {+/⍵}⌸10 20 30
+// The results are mixed: scalars form a vector, arrays are padded to a common shape.
+// R may also be a List, in which case the elements are grouped.
+//
+// If R is a table, the rows are grouped by the key columns given in L, see keyTable.
+func key(a *apl.Apl, LO, _ apl.Value) apl.Function {
+	f := LO.(apl.Function)
+	derived := func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		if t, ok := R.(apl.Table); ok {
+			return keyTable(a, f, L, t)
+		}
+		if L == nil {
+			keys, n := majorCells(R)
+			groups, idx := group(a, keys, n)
+			if groups == nil {
+				return apl.EmptyArray{}, nil
+			}
+			results := make([]apl.Value, len(groups))
+			for i, g := range groups {
+				ix := apl.IntArray{Dims: []int{len(g)}, Ints: make([]int, len(g))}
+				for k, n := range g {
+					ix.Ints[k] = n + a.Origin
+				}
+				v, err := f.Call(a, keys(idx[i]), ix)
+				if err != nil {
+					return nil, err
+				}
+				results[i] = v
+			}
+			return mixResults(a, results)
+		}
+
+		keys, nl := majorCells(L)
+		cells, nr := majorCells(R)
+		if nl != nr {
+			return nil, fmt.Errorf("key: length error: %d != %d", nl, nr)
+		}
+		groups, idx := group(a, keys, nl)
+		if groups == nil {
+			return apl.EmptyArray{}, nil
+		}
+		results := make([]apl.Value, len(groups))
+		for i, g := range groups {
+			v, err := f.Call(a, keys(idx[i]), selectCells(a, R, cells, g))
+			if err != nil {
+				return nil, err
+			}
+			results[i] = v
+		}
+		return mixResults(a, results)
+	}
+	return function(derived)
+}
+
+// majorCells returns a function that returns the i'th major cell of V
+// and the number of major cells.
+// A scalar has a single major cell.
+func majorCells(V apl.Value) (func(int) apl.Value, int) {
+	if l, ok := V.(apl.List); ok {
+		return func(i int) apl.Value { return l[i] }, len(l)
+	}
+	ar, ok := V.(apl.Array)
+	if ok == false {
+		return func(int) apl.Value { return V }, 1
+	}
+	shape := ar.Shape()
+	if len(shape) == 0 {
+		return nil, 0
+	} else if len(shape) == 1 {
+		return func(i int) apl.Value { return ar.At(i) }, shape[0]
+	}
+	cs := shape[1:]
+	size := apl.Prod(cs)
+	return func(i int) apl.Value {
+		cell := apl.MakeArray(ar, append([]int(nil), cs...))
+		for k := 0; k < size; k++ {
+			cell.Set(k, ar.At(i*size+k).Copy())
+		}
+		return cell
+	}, shape[0]
+}
+
+// selectCells returns the major cells of V at the given indexes.
+// The result has the same rank as V.
+func selectCells(a *apl.Apl, V apl.Value, cell func(int) apl.Value, idx []int) apl.Value {
+	if _, ok := V.(apl.List); ok {
+		l := make(apl.List, len(idx))
+		for i, n := range idx {
+			l[i] = cell(n).Copy()
+		}
+		return l
+	}
+	ar, ok := V.(apl.Array)
+	if ok == false {
+		return a.UnifyArray(apl.MixedArray{Dims: []int{1}, Values: []apl.Value{V}})
+	}
+	shape := apl.CopyShape(ar)
+	size := apl.Prod(shape[1:])
+	if len(shape) == 1 {
+		size = 1
+	}
+	shape[0] = len(idx)
+	res := apl.MakeArray(ar, shape)
+	for i, n := range idx {
+		for k := 0; k < size; k++ {
+			res.Set(i*size+k, ar.At(n*size+k).Copy())
+		}
+	}
+	return res
+}
+
+// group returns the indexes of the cells for each unique key, in order of first occurrence.
+// It also returns the index of the first occurrence of each group.
+// Keys are compared with match.
+// If all keys are scalars of the same type, they are grouped by their value at full precision,
+// without comparison tolerance, see scalarKeys.
+func group(a *apl.Apl, key func(int) apl.Value, n int) ([][]int, []int) {
+	var groups [][]int
+	var first []int
+	if keys, ok := scalarKeys(key, n); ok {
+		m := make(map[string]int)
+		for i, s := range keys {
+			if k, ok := m[s]; ok {
+				groups[k] = append(groups[k], i)
+			} else {
+				m[s] = len(first)
+				first = append(first, i)
+				groups = append(groups, []int{i})
+			}
+		}
+		return groups, first
+	}
+	for i := 0; i < n; i++ {
+		v := key(i)
+		found := false
+		for k, f := range first {
+			if isMatch(a, v, key(f)) {
+				groups[k] = append(groups[k], i)
+				found = true
+				break
+			}
+		}
+		if found == false {
+			first = append(first, i)
+			groups = append(groups, []int{i})
+		}
+	}
+	return groups, first
+}
+
+// scalarKeys formats the keys with full precision, if they are scalars of the same type.
+// Keys of mixed types, such as Int and Float, or nested keys are compared pairwise with match.
+func scalarKeys(key func(int) apl.Value, n int) ([]string, bool) {
+	var t reflect.Type
+	f := apl.Format{PP: -1}
+	keys := make([]string, n)
+	for i := range keys {
+		v := key(i)
+		switch v.(type) {
+		case apl.Array, apl.List, apl.Function:
+			return nil, false
+		}
+		if i == 0 {
+			t = reflect.TypeOf(v)
+		} else if reflect.TypeOf(v) != t {
+			return nil, false
+		}
+		keys[i] = v.String(f)
+	}
+	return keys, true
+}
+
+// isMatch compares two values with the match primitive ≡.
+func isMatch(a *apl.Apl, x, y apl.Value) bool {
+	v, err := apl.Primitive("≡").Call(a, x, y)
+	if err != nil {
+		return false
+	}
+	b, ok := v.(apl.Bool)
+	return ok && bool(b)
+}
+
+// mixResults combines the results of each group.
+// If all results are scalars, they form a vector.
+// Otherwise each result is a major cell of the result, padded to the largest shape.
+// If any result is a list, a list is returned.
+func mixResults(a *apl.Apl, results []apl.Value) (apl.Value, error) {
+	var common []int
+	for _, v := range results {
+		if _, ok := v.(apl.List); ok {
+			return apl.List(results), nil
+		}
+		if ar, ok := v.(apl.Array); ok {
+			s := ar.Shape()
+			if d := len(s) - len(common); d > 0 {
+				common = append(make([]int, d), common...)
+			}
+			for n := range s {
+				k := len(common) - len(s) + n
+				if s[n] > common[k] {
+					common[k] = s[n]
+				}
+			}
+		}
+	}
+	if len(common) == 0 {
+		return a.UnifyArray(apl.MixedArray{Dims: []int{len(results)}, Values: results}), nil
+	}
+	size := apl.Prod(common)
+	res := apl.NewMixed(append([]int{len(results)}, common...))
+	for i, v := range results {
+		ar, ok := v.(apl.Array)
+		if ok == false {
+			ar = apl.MixedArray{Dims: []int{1}, Values: []apl.Value{v}}
+		}
+		shape := apl.CopyShape(ar)
+		for len(shape) < len(common) {
+			shape = append([]int{1}, shape...)
+		}
+		if rs, ok := ar.(apl.Reshaper); ok {
+			ar = rs.Reshape(shape).(apl.Array)
+		}
+		idx := apl.IntArray{Dims: []int{len(common)}, Ints: append([]int(nil), common...)}
+		ar, err := Take(a, idx, ar, nil)
+		if err != nil {
+			return nil, err
+		}
+		for k := 0; k < size; k++ {
+			res.Values[i*size+k] = ar.At(k).Copy()
+		}
+	}
+	return a.UnifyArray(res), nil
+}
+
+// keyTable groups the rows of a table by the key columns given in L.
+// L is a single column name or a vector of names.
+// If L is nil, the table is grouped by the first column.
+//
+// For each group, f is called with a dict that contains the values of the key columns as ⍺,
+// and the sub-table of the remaining columns as ⍵.
+// It must return a table or a dict, which represents a single row.
+// The result table contains the key columns followed by the columns returned by f.
+//	`Sym {+/⍵}⌸T
+func keyTable(a *apl.Apl, f apl.Function, L apl.Value, t apl.Table) (apl.Value, error) {
+	all := t.Keys()
+	var names []apl.Value
+	if L == nil {
+		if len(all) == 0 {
+			return nil, fmt.Errorf("key: table has no columns")
+		}
+		names = all[:1]
+	} else if ar, ok := L.(apl.Array); ok {
+		for i := 0; i < ar.Size(); i++ {
+			names = append(names, ar.At(i))
+		}
+	} else {
+		names = []apl.Value{L}
+	}
+	var keycols []apl.Array
+	for _, k := range names {
+		col, ok := t.At(k).(apl.Array)
+		if ok == false {
+			return nil, fmt.Errorf("key: table has no column %s", k.String(a.Format))
+		}
+		keycols = append(keycols, col)
+	}
+	var rest []apl.Value
+	for _, k := range all {
+		iskey := false
+		for _, n := range names {
+			if k == n {
+				iskey = true
+			}
+		}
+		if iskey == false {
+			rest = append(rest, k)
+		}
+	}
+
+	row := func(i int) apl.Value {
+		v := make([]apl.Value, len(keycols))
+		for k, col := range keycols {
+			v[k] = col.At(i)
+		}
+		return apl.MixedArray{Dims: []int{len(v)}, Values: v}
+	}
+	groups, _ := group(a, row, t.Rows)
+
+	var rescols []apl.Value                    // result columns returned by f
+	columns := make(map[apl.Value][]apl.Value) // values of all result columns
+	for _, g := range groups {
+		alpha := apl.Dict{K: make([]apl.Value, len(names)), M: make(map[apl.Value]apl.Value)}
+		for k, key := range names {
+			alpha.K[k] = key.Copy()
+			alpha.M[key.Copy()] = keycols[k].At(g[0]).Copy()
+		}
+		sub, err := tableRows(t, rest, g)
+		if err != nil {
+			return nil, err
+		}
+		v, err := f.Call(a, &alpha, sub)
+		if err != nil {
+			return nil, err
+		}
+
+		var d apl.Object
+		rows := 1
+		if rt, ok := v.(apl.Table); ok {
+			d, rows = rt.Dict, rt.Rows
+		} else if o, ok := v.(apl.Object); ok {
+			d = o
+		} else {
+			return nil, fmt.Errorf("key: table function must return a table or a dict: %T", v)
+		}
+		keys := d.Keys()
+		if rescols == nil {
+			rescols = keys
+		} else if len(keys) != len(rescols) {
+			return nil, fmt.Errorf("key: table function returns different columns")
+		}
+		for k, key := range names {
+			for i := 0; i < rows; i++ {
+				columns[key] = append(columns[key], keycols[k].At(g[0]).Copy())
+			}
+		}
+		for i, key := range keys {
+			if key != rescols[i] {
+				return nil, fmt.Errorf("key: table function returns different columns")
+			}
+			col := d.At(key)
+			if ar, ok := col.(apl.Array); ok && ar.Size() == rows {
+				for n := 0; n < rows; n++ {
+					columns[key] = append(columns[key], ar.At(n).Copy())
+				}
+			} else if ok == false && rows == 1 {
+				columns[key] = append(columns[key], col.Copy())
+			} else {
+				return nil, fmt.Errorf("key: column %s has the wrong length", key.String(a.Format))
+			}
+		}
+	}
+
+	d := apl.Dict{M: make(map[apl.Value]apl.Value)}
+	numrows := 0
+	for _, key := range append(names, rescols...) {
+		if _, ok := d.M[key]; ok {
+			return nil, fmt.Errorf("key: duplicate column %s", key.String(a.Format))
+		}
+		v := columns[key]
+		u, ok := a.Unify(apl.MixedArray{Dims: []int{len(v)}, Values: v}, true)
+		if ok == false {
+			return nil, fmt.Errorf("key: column %s cannot be unified", key.String(a.Format))
+		}
+		numrows = len(v)
+		d.K = append(d.K, key.Copy())
+		d.M[key.Copy()] = u
+	}
+	return apl.Table{Dict: &d, Rows: numrows}, nil
+}
+
+// tableRows returns a table with the given columns and rows of t.
+func tableRows(t apl.Table, keys []apl.Value, rows []int) (apl.Table, error) {
+	d := apl.Dict{K: make([]apl.Value, len(keys)), M: make(map[apl.Value]apl.Value)}
+	for i, key := range keys {
+		src, ok := t.At(key).(apl.Array)
+		if ok == false {
+			return apl.Table{}, fmt.Errorf("table column is not an array: %T", t.At(key))
+		}
+		col := apl.MakeArray(src, []int{len(rows)})
+		for n, m := range rows {
+			if err := col.Set(n, src.At(m).Copy()); err != nil {
+				return apl.Table{}, err
+			}
+		}
+		d.K[i] = key.Copy()
+		d.M[key.Copy()] = col
+	}
+	return apl.Table{Dict: &d, Rows: len(rows)}, nil
+}
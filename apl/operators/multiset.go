@@ -0,0 +1,87 @@
+package operators
+
+import (
+	"fmt"
+
+	"github.com/ktye/iv/apl"
+	. "github.com/ktye/iv/apl/domain"
+)
+
+func init() {
+	register(operator{
+		symbol:  "⍦",
+		Domain:  MonadicOp(IsPrimitive("∩")),
+		doc:     "multiset intersection",
+		derived: multiset,
+	})
+	register(operator{
+		symbol:  "⍦",
+		Domain:  MonadicOp(IsPrimitive("~")),
+		doc:     "multiset without",
+		derived: multiset,
+	})
+	register(operator{
+		symbol:  "⍦",
+		Domain:  MonadicOp(IsPrimitive("∪")),
+		doc:     "multiset union",
+		derived: multiset,
+	})
+}
+
+// multiset derives set functions that respect duplicates.
+// Each major cell of R can be matched only once by a major cell of L:
+//	1 1 1 2∩⍦1 1 3   1 1
+//	1 1 1 2~⍦1 1 3   1 2
+//	1 1 1 2∪⍦1 1 3   1 1 1 2 3
+// The arguments may be vectors, lists or higher rank arrays.
+func multiset(a *apl.Apl, LO, _ apl.Value) apl.Function {
+	p := LO.(apl.Primitive)
+	derived := func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		if L == nil {
+			return nil, fmt.Errorf("multiset: derived function %s⍦ must be called dyadically", p)
+		}
+		if p == "∪" {
+			// L∪⍦R ←→ L⍪R~⍦L
+			rest, err := multisetSelect(a, R, L, false)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := L.(apl.List); ok {
+				return apl.Primitive(",").Call(a, L, rest)
+			}
+			return apl.Primitive("⍪").Call(a, L, rest)
+		}
+		return multisetSelect(a, L, R, p == "∩")
+	}
+	return function(derived)
+}
+
+// multisetSelect returns the major cells of L which have (keep is true)
+// or have not (keep is false) a partner in R.
+// Each cell of R is used only once.
+func multisetSelect(a *apl.Apl, L, R apl.Value, keep bool) (apl.Value, error) {
+	if _, ok := L.(apl.Table); ok {
+		return nil, fmt.Errorf("multiset: tables are not supported")
+	} else if _, ok := R.(apl.Table); ok {
+		return nil, fmt.Errorf("multiset: tables are not supported")
+	}
+	lc, nl := majorCells(L)
+	rc, nr := majorCells(R)
+	used := make([]bool, nr)
+	var idx []int
+	for i := 0; i < nl; i++ {
+		v := lc(i)
+		found := false
+		for k := 0; k < nr; k++ {
+			if used[k] == false && isMatch(a, v, rc(k)) {
+				used[k] = true
+				found = true
+				break
+			}
+		}
+		if found == keep {
+			idx = append(idx, i)
+		}
+	}
+	return selectCells(a, L, lc, idx), nil
+}
@@ -16,9 +16,6 @@ func init() {
 	})
 }
 
-// TODO: should there be a limit? How to set/change it?
-const powerlimit = 1000
-
 func power(a *apl.Apl, f, g apl.Value) apl.Function {
 	derived := func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		f := f.(apl.Function)
@@ -30,32 +27,22 @@ func power(a *apl.Apl, f, g apl.Value) apl.Function {
 			if ok == false {
 				return nil, fmt.Errorf("power: non-function RO must be an integer: %T", g)
 			}
-			n := int(nv.(apl.Int))
-			if n < 0 {
-				return nil, fmt.Errorf("power: function inverse is not implemented")
-			} else if n == 0 {
-				return R, nil
-			}
-			var err error
-			v := R
-			for i := 0; i < n; i++ {
-				v, err = f.Call(a, L, v)
-				if err != nil {
-					return nil, err
-				}
-			}
-			return v.Copy(), nil
+			return repeat(a, f, L, R, int(nv.(apl.Int)))
 		} else {
 			// RO g is a function.
 			var err error
 			var fR, v apl.Value
 			r := R
 			m := 0
+			limit := powerLimit(a)
 			for {
-				if m > powerlimit {
+				if m > limit {
 					return nil, fmt.Errorf("power: recusion limit exceeded")
 				}
 				m++
+				if err := a.Check(); err != nil {
+					return nil, err
+				}
 				fR, err = f.Call(a, L, r)
 				if err != nil {
 					return nil, err
@@ -78,5 +65,60 @@ func power(a *apl.Apl, f, g apl.Value) apl.Function {
 			}
 		}
 	}
-	return function(derived)
+	if _, ok := g.(apl.Function); ok {
+		return function(derived)
+	}
+
+	// f⍣n has the inverse f⍣-n.
+	inverse := func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		nv, ok := ToIndex(nil).To(a, g)
+		if ok == false {
+			return nil, fmt.Errorf("power: non-function RO must be an integer: %T", g)
+		}
+		return repeat(a, f.(apl.Function), L, R, -int(nv.(apl.Int)))
+	}
+	return invertible{function(derived), function(inverse)}
+}
+
+// repeat applies f n times.
+// If n is negative, the inverse of f is applied -n times.
+func repeat(a *apl.Apl, f apl.Function, L, R apl.Value, n int) (apl.Value, error) {
+	call := f.Call
+	if n < 0 {
+		n = -n
+		call = func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+			return apl.CallInverse(a, f, L, R)
+		}
+	}
+	if n == 0 {
+		return R, nil
+	}
+	var err error
+	v := R
+	for i := 0; i < n; i++ {
+		if err := a.Check(); err != nil {
+			return nil, err
+		}
+		v, err = call(a, L, v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return v.Copy(), nil
+}
+
+// powerLimit returns the maximal number of iterations of the power operator.
+// It may be lowered by the variant option limit:
+//	f⍣≡⍠(`limit#10)
+// If Limits.Power is not set, it may also be raised.
+func powerLimit(a *apl.Apl) int {
+	limit := a.PowerLimit()
+	if v := a.Option("limit"); v != nil {
+		if n, ok := ToIndex(nil).To(a, v); ok {
+			if i := int(n.(apl.Int)); i < limit || a.Limits.Power == 0 {
+				limit = i
+			}
+		}
+	}
+	return limit
 }
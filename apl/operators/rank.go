@@ -297,7 +297,7 @@ func sendParseSubArray(a *apl.Apl, rank int, in apl.Channel) (apl.Value, error)
 			if err == io.EOF || err == io.ErrClosedPipe {
 				return
 			} else if err != nil {
-				out[0] <- apl.Error{err}
+				out[0] <- apl.Error{E: err}
 				return
 			}
 			select {
@@ -240,9 +240,65 @@ func reduct(a *apl.Apl, f apl.Function, l, r apl.Value, axis int) (apl.Value, er
 
 // ScanArray is the derived function f\ .
 func scanArray(a *apl.Apl, f apl.Value, axis int) apl.Function {
-	return function(func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	derived := function(func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		return scanfunc(a, f.(apl.Function), L, R, axis)
 	})
+	if p, ok := f.(apl.Primitive); ok && p == "+" {
+		return invertible{derived, function(func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+			return unscan(a, L, R, axis)
+		})}
+	}
+	return derived
+}
+
+// unscan is the inverse of the sum scan +\ along the axis.
+// It returns the differences of successive elements.
+func unscan(a *apl.Apl, L, R apl.Value, axis int) (apl.Value, error) {
+	if L != nil {
+		return nil, fmt.Errorf("scan: derived function is not defined for dyadic context")
+	}
+	if _, ok := R.(apl.Axis); ok {
+		if r, n, err := splitAxis(a, R); err != nil {
+			return nil, err
+		} else {
+			R = r
+			if len(n) != 1 {
+				return nil, fmt.Errorf("scan with axis: axis must be a scalar")
+			}
+			axis = n[0]
+		}
+	}
+	ar, ok := R.(apl.Array)
+	if ok == false {
+		return R, nil
+	}
+	shape := ar.Shape()
+	if len(shape) == 0 {
+		return R, nil
+	}
+	if axis < 0 {
+		axis = len(shape) + axis
+	}
+	if axis < 0 || axis >= len(shape) {
+		return nil, fmt.Errorf("scan: axis rank is %d but axis %d", len(shape), axis)
+	}
+	stride := 1
+	for _, n := range shape[axis+1:] {
+		stride *= n
+	}
+	res := apl.NewMixed(apl.CopyShape(ar))
+	for i := range res.Values {
+		if (i/stride)%shape[axis] == 0 {
+			res.Values[i] = ar.At(i).Copy()
+			continue
+		}
+		v, err := apl.Primitive("-").Call(a, ar.At(i), ar.At(i-stride))
+		if err != nil {
+			return nil, err
+		}
+		res.Values[i] = v
+	}
+	return a.UnifyArray(res), nil
 }
 
 func scanfunc(a *apl.Apl, f apl.Function, L, R apl.Value, axis int) (apl.Value, error) {
@@ -342,6 +398,9 @@ func scanChannel(a *apl.Apl, f apl.Function, c apl.Channel) (apl.Value, error) {
 	var err error
 	var s apl.Value
 	for v := range c[0] {
+		if err = a.Check(); err != nil {
+			break
+		}
 		if vec == nil {
 			vec = append(vec, v.Copy())
 		} else {
@@ -414,21 +473,28 @@ func Replicate(a *apl.Apl, L, R apl.Value, axis int) (apl.Value, error) {
 	// Replicate along axis.
 	shape := apl.CopyShape(ar)
 	count := 0
-	var axismap []int
+	for _, n := range ai.Ints {
+		if n < 0 {
+			n = -n
+		}
+		count += n
+	}
+	shape[axis] = count
+	if err := a.CheckSize(apl.Prod(shape)); err != nil {
+		return nil, err
+	}
+	axismap := make([]int, 0, count)
 	for k, n := range ai.Ints {
 		if n > 0 {
-			count += n
 			for i := 0; i < n; i++ {
 				axismap = append(axismap, k)
 			}
 		} else if n < 0 {
-			count += -n
 			for i := 0; i < -n; i++ {
 				axismap = append(axismap, -1)
 			}
 		}
 	}
-	shape[axis] = int(count)
 	res := apl.MakeArray(ar, shape)
 	var zero apl.Value = apl.Int(0)
 	if u, ok := res.(apl.Uniform); ok {
@@ -653,6 +719,9 @@ func reduce(a *apl.Apl, vec []apl.Value, d apl.Function) (apl.Value, error) {
 	var err error
 	v := vec[len(vec)-1].Copy()
 	for i := len(vec) - 2; i >= 0; i-- {
+		if err := a.Check(); err != nil {
+			return nil, err
+		}
 		v, err = d.Call(a, vec[i].Copy(), v.Copy())
 		if err != nil {
 			return nil, err
@@ -668,6 +737,9 @@ func reduceChannel(a *apl.Apl, L apl.Value, f apl.Function, c apl.Channel) (apl.
 	var res apl.Value
 	var err error
 	for v := range c[0] {
+		if err = a.Check(); err != nil {
+			break
+		}
 		if res == nil {
 			res = v.Copy()
 		} else {
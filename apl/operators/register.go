@@ -78,3 +78,14 @@ type function func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error)
 func (f function) Call(a *apl.Apl, l, r apl.Value) (apl.Value, error) {
 	return f(a, l, r)
 }
+
+// invertible is a derived function with an inverse.
+// It implements apl.Inverter.
+type invertible struct {
+	function
+	inverse function
+}
+
+func (f invertible) CallInverse(a *apl.Apl, l, r apl.Value) (apl.Value, error) {
+	return f.inverse(a, l, r)
+}
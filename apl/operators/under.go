@@ -0,0 +1,56 @@
+package operators
+
+import (
+	"github.com/ktye/iv/apl"
+	. "github.com/ktye/iv/apl/domain"
+)
+
+func init() {
+	register(operator{
+		symbol:  "⍢",
+		Domain:  DyadicOp(Split(Function(nil), Function(nil))),
+		doc:     "under, dual",
+		derived: under,
+	})
+}
+
+// under applies f after the transformation g and undoes the transformation with the inverse of g:
+//	f⍢g R   ←→  g⍣¯1 f g R
+//	L f⍢g R ←→  g⍣¯1 (g L) f (g R)
+// Example: add in log space, which multiplies:
+//	2 +⍢⍟ 3
+// The inverse of f⍢g is f⍣¯1⍢g.
+func under(a *apl.Apl, LO, RO apl.Value) apl.Function {
+	f := LO.(apl.Function)
+	g := RO.(apl.Function)
+	apply := func(a *apl.Apl, L, R apl.Value, inverse bool) (apl.Value, error) {
+		r, err := g.Call(a, nil, R)
+		if err != nil {
+			return nil, err
+		}
+		var l apl.Value
+		if L != nil {
+			l, err = g.Call(a, nil, L)
+			if err != nil {
+				return nil, err
+			}
+		}
+		var v apl.Value
+		if inverse {
+			v, err = apl.CallInverse(a, f, l, r)
+		} else {
+			v, err = f.Call(a, l, r)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return apl.CallInverse(a, g, nil, v)
+	}
+	derived := func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		return apply(a, L, R, false)
+	}
+	inverse := func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		return apply(a, L, R, true)
+	}
+	return invertible{function(derived), function(inverse)}
+}
@@ -0,0 +1,28 @@
+package operators
+
+import (
+	"github.com/ktye/iv/apl"
+	. "github.com/ktye/iv/apl/domain"
+)
+
+func init() {
+	register(operator{
+		symbol:  "⍠",
+		Domain:  DyadicOp(Split(Function(nil), IsObject(Not(IsTable(nil))))),
+		doc:     "variant, options",
+		derived: variant,
+	})
+}
+
+// variant attaches the options in the dict RO to the function LO.
+// Primitives, operators and go functions query them during the call, see apl.Options.
+// The options are not visible to the functions, that LO calls in turn.
+//	⍋⍠(`collation#'cab')'abcab'
+//	⍕⍠(`PP#3)○1
+//	f⍣≡⍠(`limit#10)⊢R
+func variant(a *apl.Apl, LO, RO apl.Value) apl.Function {
+	derived := func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		return a.CallVariant(LO.(apl.Function), RO.(apl.Object), L, R)
+	}
+	return function(derived)
+}
@@ -0,0 +1,255 @@
+package apl
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// The page size limits the output of arrays and tables.
+// It is set by ⎕PW (page width) and ⎕PH (page height, the maximum number of rows).
+// A value of 0 does not limit the output, which is the default for a new interpreter.
+// If only ⎕PH is set, the page width is 80.
+//
+// Vectors that are wider than the page are continued on the next line,
+// indented by 6 spaces.
+// Matrices and tables that are wider than the page are folded into column blocks,
+// which are printed below each other, separated by an empty line.
+//
+// Arrays that need more lines than ⎕PH are elided.
+// Only the leading rows and columns that fit are formatted, missing columns are
+// marked with … at the end of each row.
+// The last line contains … followed by the shape:
+//	⎕PW←30 ⋄ ⎕PH←4 ⋄ 100 100⍴⍳10000
+//	   1   2   3   4   5   6   7 …
+//	 101 102 103 104 105 106 107 …
+//	 201 202 203 204 205 206 207 …
+//	… ⍴100 100
+
+// setPage sets ⎕PW or ⎕PH.
+func (a *Apl) setPage(name string, v Value) error {
+	n, ok := v.(Number)
+	if ok == false {
+		return fmt.Errorf("cannot set %s: %T", name, v)
+	}
+	i, ok := n.ToIndex()
+	if ok == false || i < 0 {
+		return fmt.Errorf("cannot set %s: must be a non-negative integer", name)
+	}
+	if name == "⎕PW" {
+		a.Format.PW = i
+	} else {
+		a.Format.PH = i
+	}
+	return nil
+}
+
+// paged returns true, if the output is limited by the page size.
+func (f Format) paged() bool {
+	return f.PW > 0 || f.PH > 0
+}
+
+// defaultPW is the page width, if only the page height is set.
+const defaultPW = 80
+
+// page returns the format used for paging.
+// The page width is set to the default, if only the page height is limited.
+func (f Format) page() Format {
+	if f.PW == 0 && f.PH > 0 {
+		f.PW = defaultPW
+	}
+	return f
+}
+
+// elision returns the last line of an elided array.
+func elision(shape []int) string {
+	return "… ⍴" + boxShape(shape)
+}
+
+// pageVector formats a vector that is continued on the next line, if it is wider than ⎕PW.
+// Only the elements on the first ⎕PH lines are formatted.
+func pageVector(f Format, v Array) string {
+	const indent = "      "
+	n := v.Size()
+	var lines []string
+	var b strings.Builder
+	w := 0
+	for i := 0; i < n; i++ {
+		s := v.At(i).String(f)
+		k := utf8.RuneCountInString(s)
+		if i > 0 && f.PW > 0 && w+1+k > f.PW && w > len(indent) {
+			lines = append(lines, b.String())
+			if f.PH > 0 && len(lines) == f.PH {
+				lines[len(lines)-1] = elision([]int{n})
+				return strings.Join(lines, "\n")
+			}
+			b.Reset()
+			b.WriteString(indent)
+			w = len(indent)
+		} else if i > 0 {
+			b.WriteByte(' ')
+			w++
+		}
+		b.WriteString(s)
+		w += k
+	}
+	return strings.Join(append(lines, b.String()), "\n")
+}
+
+// pageArray formats an array of rank 2 or higher with limits given by ⎕PW and ⎕PH.
+// The layout is the same as the tabwriter in ArrayString uses:
+// each column is aligned to the right and padded by a single space.
+// Sub-arrays of rank 2 are separated by empty lines.
+func pageArray(f Format, v Array) string {
+	shape := v.Shape()
+	cols := shape[len(shape)-1]
+	rshape := shape[:len(shape)-1]
+	nrows := Prod(rshape)
+
+	// sep returns the number of empty lines before row i.
+	sep := func(i int) int {
+		n := 0
+		if i == 0 {
+			return 0
+		}
+		p := rshape[len(rshape)-1]
+		for k := len(rshape) - 2; k >= 0 && i%p == 0; k-- {
+			n++
+			p *= rshape[k]
+		}
+		return n
+	}
+	total := nrows
+	for k := 0; k < len(rshape)-1; k++ {
+		total += Prod(rshape[:k+1]) - 1
+	}
+
+	// Elide early, if even the narrowest columns cannot fit on the page.
+	elided := f.PH > 0 && total > f.PH
+	if f.PH > 0 && f.PW > 0 && total*((2*cols+f.PW-1)/f.PW) > f.PH {
+		elided = true
+	}
+
+	numcols := cols
+	if elided && f.PW > 0 && cols > f.PW/2+1 {
+		numcols = f.PW/2 + 1
+	}
+	var rows [][]string
+	lines := 0
+	for i := 0; i < nrows; i++ {
+		if elided && lines+sep(i)+1 > f.PH-1 && lines > 0 {
+			break
+		}
+		for k := sep(i); k > 0; k-- {
+			rows = append(rows, nil)
+		}
+		lines += sep(i) + 1
+		r := make([]string, numcols)
+		for k := range r {
+			r[k] = v.At(i*cols + k).String(f)
+		}
+		rows = append(rows, r)
+	}
+
+	widths := columnWidths(rows, numcols)
+	blocks := columnBlocks(widths, f.PW, 0)
+	if elided == false && f.PH > 0 && total*len(blocks)+len(blocks)-1 > f.PH {
+		elided = true
+		if n := f.PH - 1; len(rows) > n && n > 0 {
+			rows = rows[:n]
+		}
+		for len(rows) > 1 && rows[len(rows)-1] == nil {
+			rows = rows[:len(rows)-1]
+		}
+		widths = columnWidths(rows, numcols)
+	}
+	if elided == false {
+		return strings.Join(foldRows(rows, widths, blocks, true, false), "\n")
+	}
+	more := numcols < cols || len(blocks) > 1
+	if more {
+		blocks = columnBlocks(widths, f.PW, 2)
+	}
+	out := foldRows(rows, widths, blocks[:1], true, more)
+	return strings.Join(append(out, elision(shape)), "\n")
+}
+
+// columnWidths returns the maximal width of each column.
+// A nil row is an empty line, which is ignored.
+func columnWidths(rows [][]string, cols int) []int {
+	widths := make([]int, cols)
+	for _, r := range rows {
+		for k, s := range r {
+			if n := utf8.RuneCountInString(s); n > widths[k] {
+				widths[k] = n
+			}
+		}
+	}
+	return widths
+}
+
+// columnBlocks splits the columns into blocks of [start, end) that fit on the page.
+// Each column is padded by a single space.
+// The page width is reduced by reserve characters.
+// A block contains at least one column, even if it is wider than the page.
+func columnBlocks(widths []int, pw, reserve int) [][2]int {
+	if pw <= 0 {
+		return [][2]int{{0, len(widths)}}
+	}
+	var blocks [][2]int
+	start, w := 0, 0
+	for k, n := range widths {
+		if k > start && w+n+1 > pw-reserve {
+			blocks = append(blocks, [2]int{start, k})
+			start, w = k, 0
+		}
+		w += n + 1
+	}
+	return append(blocks, [2]int{start, len(widths)})
+}
+
+// foldRows formats the rows for each column block.
+// Blocks are separated by an empty line.
+// Cells are aligned to the right or to the left.
+// If more is true, each row is marked with … to indicate missing columns.
+func foldRows(rows [][]string, widths []int, blocks [][2]int, right, more bool) []string {
+	var lines []string
+	for i, blk := range blocks {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		for _, r := range rows {
+			if r == nil {
+				lines = append(lines, "")
+				continue
+			}
+			var b strings.Builder
+			for k := blk[0]; k < blk[1]; k++ {
+				s := r[k]
+				p := strings.Repeat(" ", widths[k]+1-utf8.RuneCountInString(s))
+				if right {
+					b.WriteString(p + s)
+				} else if k < blk[1]-1 || more {
+					b.WriteString(s + p)
+				} else {
+					b.WriteString(s)
+				}
+			}
+			if more && right {
+				b.WriteString(" …")
+			} else if more {
+				b.WriteString("…")
+			}
+			lines = append(lines, b.String())
+		}
+	}
+	return lines
+}
+
+// pageSize returns the value of ⎕PW or ⎕PH.
+func (a *Apl) pageSize(name string) Value {
+	if name == "⎕PW" {
+		return Int(a.Format.PW)
+	}
+	return Int(a.Format.PH)
+}
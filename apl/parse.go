@@ -9,10 +9,13 @@ import (
 )
 
 type parser struct {
-	a      *Apl
-	tokens []scan.Token
-	stack  []item
-	pos    int
+	a        *Apl
+	tokens   []scan.Token
+	stack    []item
+	pos      int
+	operands map[string]class // classes of ⍺⍺ and ⍵⍵ within a lambda operator
+	ops      map[string]class // lambda operators assigned in earlier statements
+	src      *source          // source lines for positions
 }
 
 const (
@@ -23,10 +26,12 @@ const (
 )
 
 // Item is an element of the parse stack.
-// It contains a expr with an associated class.
+// It contains a expr with an associated class and it's position in the source.
 type item struct {
-	e     expr
-	class class
+	e      expr
+	class  class
+	pos    Pos
+	strand bool // unparenthesized array, that may be extended by nested strands
 }
 type class int
 
@@ -41,7 +46,12 @@ func (c class) String() string {
 }
 
 // Parse parses the tokens to a program, which is a slice of expressions.
-func (p *parser) parse(tokens []scan.Token) (Program, error) {
+// The source contains the lines of the tokens.
+func (p *parser) parse(tokens []scan.Token, src *source) (Program, error) {
+	p.src = src
+	if p.ops == nil {
+		p.ops = make(map[string]class)
+	}
 
 	var prog Program
 	var itm item
@@ -141,9 +151,15 @@ func (p *parser) parseStatement() (item, error) {
 		case scan.Symbol:
 
 			if _, ok := p.a.primitives[Primitive(t.S)]; ok {
-				push(item{e: Primitive(t.S), class: verb}, false)
+				if err := p.a.Sandbox.checkSymbol(t.S, false); err != nil {
+					return item{}, err
+				}
+				push(item{e: Primitive(t.S), class: verb, pos: p.src.pos(t)}, false)
 			} else if ops, ok := p.a.operators[t.S]; ok {
-				i := item{e: &derived{op: t.S}, class: adverb}
+				if err := p.a.Sandbox.checkSymbol(t.S, true); err != nil {
+					return item{}, err
+				}
+				i := item{e: &derived{op: t.S}, class: adverb, pos: p.src.pos(t)}
 				if ops[0].DyadicOp() == true {
 					i.class = conjunction
 				}
@@ -152,53 +168,88 @@ func (p *parser) parseStatement() (item, error) {
 				}
 				push(i, false)
 			} else {
-				return item{}, fmt.Errorf("unknown symbol: %s", t.S)
+				return item{}, posError(fmt.Errorf("unknown symbol: %s", t.S), p.src.pos(t))
 			}
 
 		case scan.Number, scan.String, scan.Chars:
-			e, err := p.collectArray(t)
+			e, pos, err := p.collectArray(t)
 			if err != nil {
 				return item{}, err
 			}
-			push(item{e: e, class: noun}, false)
+			push(item{e: e, class: noun, pos: pos, strand: true}, false)
 
 		case scan.Identifier:
-			i := item{class: verb}
-			if ok, fok := isVarname(t.S); ok == false {
-				return item{}, fmt.Errorf("illegal variable name: %s", t.S)
+			i := item{class: verb, pos: p.src.pos(t)}
+			if c, ok := p.operandClass(t.S); ok {
+				// Operands of a lambda operator may be arrays or functions.
+				if c == noun {
+					e, pos, err := p.collectArray(t)
+					if err != nil {
+						return item{}, err
+					}
+					i.e = e
+					i.class = noun
+					i.pos = pos
+				} else {
+					i.e = fnVar(t.S)
+				}
+			} else if ok, fok := isVarname(t.S); ok == false {
+				return item{}, posError(fmt.Errorf("illegal variable name: %s", t.S), i.pos)
+			} else if err := p.a.Sandbox.checkPackage(t.S); err != nil {
+				return item{}, posError(err, i.pos)
 			} else if fok == false {
-				e, err := p.collectArray(t)
+				e, pos, err := p.collectArray(t)
 				if err != nil {
 					return item{}, err
 				}
 				i.e = e
 				i.class = noun
+				i.pos = pos
+				i.strand = true
+			} else if n := len(p.stack); n > 0 && isAssignOp(p.stack[n-1].e) {
+				// The target of an assignment is always a variable.
+				i.e = fnVar(t.S)
+			} else if c, ok := p.ops[t.S]; ok {
+				// A lambda operator assigned in an earlier statement is parsed as an operator.
+				if c != 0 {
+					i.e = &derived{dop: opVar(t.S)}
+					i.class = c
+				} else {
+					i.e = fnVar(t.S)
+				}
+			} else if op, ok := p.a.Lookup(t.S).(*lambdaOp); ok {
+				// A variable that holds a lambda operator is parsed as an operator.
+				i.e = &derived{dop: opVar(t.S)}
+				i.class = op.class()
 			} else {
 				i.e = fnVar(t.S)
 			}
 			push(i, false)
 
 		case scan.Self:
-			push(item{e: self{}, class: verb}, false)
+			push(item{e: self{}, class: verb, pos: p.src.pos(t)}, false)
 
 		case scan.LeftParen, scan.LeftBrack, scan.LeftBrace:
-			return item{}, fmt.Errorf("unexpected opening %s", t.S)
+			return item{}, posError(fmt.Errorf("unexpected opening %s", t.S), p.src.pos(t))
 
 		case scan.RightParen, scan.RightBrack, scan.RightBrace:
-			i, err := p.subStatement(t.T)
+			i, err := p.subStatement(t)
 			if err != nil {
-				return item{}, fmt.Errorf("%s", err)
+				return item{}, err
 			}
 			push(i, false)
 
 		case scan.Colon:
-			return item{}, fmt.Errorf("unexpected : outside {}")
+			return item{}, posError(fmt.Errorf("unexpected : outside {}"), p.src.pos(t))
+
+		case scan.Trap:
+			return item{}, posError(fmt.Errorf("unexpected :: outside {}"), p.src.pos(t))
 
 		case scan.Semicolon:
-			return item{}, fmt.Errorf("unexpected ; outside []")
+			return item{}, posError(fmt.Errorf("unexpected ; outside []"), p.src.pos(t))
 
 		default:
-			return item{}, fmt.Errorf("unknown token %s", t.S)
+			return item{}, posError(fmt.Errorf("unknown token %s", t.S), p.src.pos(t))
 		}
 	}
 	return item{}, fmt.Errorf("illegal parser state") // Should not be reached.
@@ -215,15 +266,25 @@ func (p *parser) pull() scan.Token {
 	return t
 }
 
-// subStatement parses a parenthesized substatement.
+// subStatement parses a parenthesized substatement, given the closing token.
 // Parens may be (), [] or {}.
-func (p *parser) subStatement(right scan.Type) (item, error) {
-
+// The position of the returned item spans the parens.
+func (p *parser) subStatement(rt scan.Token) (i item, err error) {
+	right := rt.T
 	left := map[scan.Type]scan.Type{
 		scan.RightBrace: scan.LeftBrace,
 		scan.RightParen: scan.LeftParen,
 		scan.RightBrack: scan.LeftBrack,
 	}[right]
+	pos := p.src.pos(rt)
+	defer func() {
+		if err != nil {
+			err = posError(err, pos)
+		} else {
+			i.pos = pos
+			i.strand = false
+		}
+	}()
 
 	// Pull until matching left paren. The right paren is not present anymore.
 	var tokens []scan.Token
@@ -231,6 +292,7 @@ func (p *parser) subStatement(right scan.Type) (item, error) {
 	for {
 		t := p.pull()
 		tokens = append(tokens, t)
+		pos = p.src.pos(t).join(pos)
 		switch t.T {
 		case scan.Endl:
 			return item{}, fmt.Errorf("unmatched %s", left.String())
@@ -262,7 +324,7 @@ rev:
 	}
 
 	// Create a new parser for the substatement and return it's result.
-	q := &parser{a: p.a, tokens: tokens}
+	q := p.sub(tokens)
 
 	switch left {
 	case scan.LeftParen:
@@ -288,7 +350,7 @@ func (p *parser) parseList() (item, error) {
 	}
 	lst := make(list, len(l))
 	for i := range l {
-		q := &parser{a: p.a, tokens: l[i]}
+		q := p.sub(l[i])
 		it, err := q.parseStatement()
 		if err != nil {
 			return item{}, err
@@ -307,7 +369,7 @@ func (p *parser) parseBrackets() (item, error) {
 	l := p.splitTokens(scan.Semicolon, []scan.Type{scan.LeftParen, scan.LeftBrack}, []scan.Type{scan.RightParen, scan.RightBrack})
 	spec := make(idxSpec, len(l))
 	for i := range l {
-		q := &parser{a: p.a, tokens: l[i]}
+		q := p.sub(l[i])
 		it, err := q.parseStatement()
 		if err != nil {
 			return item{}, err
@@ -327,18 +389,44 @@ func (p *parser) parseBrackets() (item, error) {
 // The outer braces are not present anymore in the parsers's tokens.
 // Lambdas are calles dfns in dyalog: DyaProg p. 131
 func (p *parser) parseLambda() (item, error) {
+	if p.operands == nil {
+		if c := p.lambdaClass(); c != verb {
+			return p.parseLambdaOp(c)
+		}
+	}
+	body, err := p.lambdaBody()
+	if err != nil {
+		return item{}, err
+	}
+	return item{e: &lambda{body: body, src: p.source()}, class: verb}, nil
+}
+
+// source returns the source of the lambda expression in the parser's token list.
+func (p *parser) source() string {
+	return "{" + scan.Source(p.tokens) + "}"
+}
+
+// lambdaBody parses the guardList of a lambda expression.
+// Lambda operators that are assigned within the body are local to it.
+func (p *parser) lambdaBody() (guardList, error) {
+	ops := make(map[string]class)
+	for name, c := range p.ops {
+		ops[name] = c
+	}
+
 	// Entries of the guardList are separated by diamonds.
 	l := p.splitTokens(scan.Diamond, []scan.Type{scan.LeftBrace}, []scan.Type{scan.RightBrace})
 	body := make(guardList, len(l))
 	for i := range l {
-		q := &parser{a: p.a, tokens: l[i]}
+		q := p.sub(l[i])
+		q.ops = ops
 		ge, ternary, err := q.guardExpr()
 		if err != nil {
-			return item{}, err
+			return nil, err
 		}
 		body[i] = ge
 		if ternary != nil && i != len(l)-1 {
-			return item{}, fmt.Errorf("lambda: ternary is only allowed as the last item")
+			return nil, fmt.Errorf("lambda: ternary is only allowed as the last item")
 		} else if ternary != nil {
 			body = append(body, &guardExpr{e: ternary})
 		}
@@ -350,20 +438,99 @@ func (p *parser) parseLambda() (item, error) {
 			body = body[:len(body)-1]
 		}
 	}
-	return item{e: &lambda{body}, class: verb}, nil
+	return body, nil
+}
+
+// lambdaClass returns the class of a lambda expression.
+// If it refers to ⍵⍵ it is a dyadic operator, if it refers to ⍺⍺ it is a monadic operator
+// and otherwise a function.
+// This includes nested lambdas, which use the operands of the enclosing operator,
+// except for nested lambdas that are assigned, which define a local operator:
+//	{f←{⍺⍺ ⍵}⋄-f ⍵}
+func (p *parser) lambdaClass() class {
+	c := verb
+	depth, local := 0, 0 // local is the depth of an assigned nested lambda
+	for i, t := range p.tokens {
+		switch t.T {
+		case scan.LeftBrace:
+			depth++
+			if local == 0 && i > 0 && p.tokens[i-1].T == scan.Symbol && p.tokens[i-1].S == "←" {
+				local = depth
+			}
+		case scan.RightBrace:
+			if depth == local {
+				local = 0
+			}
+			depth--
+		case scan.Identifier:
+			if local != 0 {
+				continue
+			} else if t.S == "⍵⍵" {
+				return conjunction
+			} else if t.S == "⍺⍺" {
+				c = adverb
+			}
+		}
+	}
+	return c
+}
+
+// parseLambdaOp parses a lambda operator.
+// The class of the operands is not known before the operator is applied.
+// The body is parsed for all combinations of operand classes.
+// Combinations that fail to parse keep their error, which is returned when
+// the operator is applied to operands of these classes.
+func (p *parser) parseLambdaOp(c class) (item, error) {
+	op := &lambdaOp{dyadic: c == conjunction, src: p.source()}
+	ok := false
+	for k := range op.body {
+		q := p.sub(p.tokens)
+		q.operands = map[string]class{"⍺⍺": verb, "⍵⍵": verb}
+		if k&1 != 0 {
+			q.operands["⍺⍺"] = noun
+		}
+		if k&2 != 0 {
+			q.operands["⍵⍵"] = noun
+		}
+		op.body[k], op.err[k] = q.lambdaBody()
+		if op.err[k] == nil {
+			ok = true
+		}
+	}
+	if ok == false {
+		return item{}, op.err[0]
+	}
+	return item{e: &derived{dop: op}, class: c}, nil
+}
+
+// operandClass returns the parse class of the operands ⍺⍺ and ⍵⍵ within a lambda operator.
+// It returns false, if the string is not an operand identifier.
+func (p *parser) operandClass(s string) (class, bool) {
+	if s != "⍺⍺" && s != "⍵⍵" {
+		return 0, false
+	}
+	if c, ok := p.operands[s]; ok {
+		return c, true
+	}
+	return verb, true
 }
 
 // GuardExpr parses a guarded expression, which is part of a lambda expression.
 //	cond:expr
 //	cond:expr:expr2 (short ternary form, only for the last in the list).
+//	codes::expr     (error guard)
 func (p *parser) guardExpr() (*guardExpr, expr, error) {
+	if l := p.splitTokens(scan.Trap, []scan.Type{scan.LeftBrace}, []scan.Type{scan.RightBrace}); len(l) > 1 {
+		ge, err := p.errorGuard(l)
+		return ge, nil, err
+	}
 	l := p.splitTokens(scan.Colon, []scan.Type{scan.LeftBrace}, []scan.Type{scan.RightBrace})
 	if len(l) > 3 {
 		return nil, nil, fmt.Errorf("lambda has too many colons")
 	}
 	ge := &guardExpr{}
 	for i := range l {
-		q := &parser{a: p.a, tokens: l[i]}
+		q := p.sub(l[i])
 		item, err := q.parseStatement()
 		if err != nil {
 			return nil, nil, err
@@ -380,17 +547,41 @@ func (p *parser) guardExpr() (*guardExpr, expr, error) {
 	return ge, nil, nil
 }
 
+// errorGuard parses an error guard from the tokens left and right of ::.
+func (p *parser) errorGuard(l [][]scan.Token) (*guardExpr, error) {
+	if len(l) != 2 {
+		return nil, fmt.Errorf("lambda: error guard has too many ::")
+	}
+	ge := &guardExpr{trap: true}
+	for i := range l {
+		q := p.sub(l[i])
+		item, err := q.parseStatement()
+		if err != nil {
+			return nil, err
+		} else if item.e == nil {
+			return nil, fmt.Errorf("lambda: error guard is incomplete")
+		}
+		if i == 0 {
+			ge.cond = item.e
+		} else {
+			ge.e = item.e
+		}
+	}
+	return ge, nil
+}
+
 // collectArray pulls tokens from the parser that form an array starting with the given right end token.
 //
 // TODO: this is not correct: Vector binding is not stronger than right operand binding
 // APL2 p 36: LO DOP A B ←→ (LO DOP A) B not LO DOP (A B)
 // Check if the token left to the first number is a DOP.
-func (p *parser) collectArray(right scan.Token) (expr, error) {
+func (p *parser) collectArray(right scan.Token) (expr, Pos, error) {
 	// Push back the right token.
 	p.tokens = append(p.tokens, right)
 
 	// The array is collected in reverse order.
 	var ar array
+	var pos Pos
 loop:
 	for {
 		if len(p.tokens) == 0 {
@@ -400,7 +591,7 @@ loop:
 		switch t.T {
 		case scan.Number:
 			if n, err := p.a.Tower.Parse(t.S); err != nil {
-				return nil, err
+				return nil, pos, posError(err, p.src.pos(t))
 			} else {
 				ar = append(ar, n)
 			}
@@ -417,13 +608,17 @@ loop:
 					chars[i] = String(string(runes[k]))
 				}
 				if ar != nil && len(chars) > 1 {
-					return nil, fmt.Errorf("only scalars can be added to an array")
+					return nil, pos, posError(fmt.Errorf("only scalars can be added to an array"), p.src.pos(t))
 				}
 				ar = append(ar, chars...)
 			}
 
 		case scan.Identifier:
-			if ok, fok := isVarname(t.S); ok == false || fok == true {
+			if c, ok := p.operandClass(t.S); ok {
+				if c != noun {
+					break loop
+				}
+			} else if ok, fok := isVarname(t.S); ok == false || fok == true {
 				break loop
 			}
 			ar = append(ar, numVar{t.S})
@@ -432,10 +627,11 @@ loop:
 			break loop
 		}
 		p.pull() // Remove the token that has just been processed.
+		pos = p.src.pos(t).join(pos)
 	}
 
 	if ar == nil {
-		return EmptyArray{}, nil
+		return EmptyArray{}, pos, nil
 	}
 
 	// Reverse the array to the normal left to right order.
@@ -446,9 +642,9 @@ loop:
 
 	// If there is only 1 item, return it unboxed.
 	if len(ar) == 1 {
-		return ar[0], nil
+		return ar[0], pos, nil
 	}
-	return ar, nil
+	return ar, pos, nil
 }
 
 // Reduce tries to reduce the partial right tail of the stack.
@@ -462,16 +658,53 @@ func (p *parser) reduce(last bool) error {
 	if err := p.resolveBrackets(); err != nil {
 		return err
 	}
+	p.resolveStrands(last)
 	p.resolveOperators(last)
 	p.resolveArrays(last)
 	p.resolveFunctions(last)
 
-	if last && len(p.stack) > 1 {
-		return fmt.Errorf("cannot reduce expression")
+	if _, ok := p.opAssignment(); last && len(p.stack) > 1 && ok == false {
+		return posError(fmt.Errorf("cannot reduce expression"), p.leftItem(0).pos)
 	}
 	return nil
 }
 
+// ResolveStrands joins adjacent nouns to a nested array, if nested arrays are enabled.
+//	(1 2)(3 4)
+//	1 2 (3 4) 5
+// Unparenthesized arrays are spliced, parenthesized expressions become a single item.
+// A noun that is the right operand of a dyadic operator is not joined:
+//	f⍣2 (1 2) ←→ (f⍣2)(1 2)
+func (p *parser) resolveStrands(last bool) {
+	if p.a.Nested == false {
+		return
+	}
+	n := 1
+	if last == false {
+		if len(p.stack) < 3 || p.leftItem(0).class == conjunction {
+			return
+		}
+	} else if len(p.stack) < 2 {
+		return
+	}
+	if last {
+		n = 0
+	}
+	l, r := p.leftItem(n), p.leftItem(n+1)
+	if l.class != noun || r.class != noun {
+		return
+	}
+	items := func(i item) array {
+		if ar, ok := i.e.(array); ok && i.strand {
+			return ar
+		}
+		return array{i.e}
+	}
+	ar := append(items(l), items(r)...)
+	p.setLeft(n+1, item{e: ar, class: noun, pos: l.pos.join(r.pos), strand: true})
+	p.removeLeft(n)
+}
+
 // ResolveBrackets changes the order of bracket expressions.
 // Brackets are parsed as idxSpec following an Identifier or as
 // an axis specification following a primitive or an operator.
@@ -483,49 +716,53 @@ func (p *parser) resolveBrackets() error {
 	}
 	if spec, ok := p.leftItem(1).e.(idxSpec); ok {
 		l := p.leftItem(0)
+		sp := p.leftItem(1).pos
+		pos := l.pos.join(sp)
 		if id, ok := l.e.(numVar); ok && l.class == noun {
 			fn := &function{
 				Function: Primitive("⌷"),
 				left:     spec,
 				right:    id,
+				pos:      sp,
 			}
-			p.setLeft(1, item{e: fn, class: noun})
+			p.setLeft(1, item{e: fn, class: noun, pos: pos})
 			p.removeLeft(0)
 			return nil
 		} else if _, ok := l.e.(Primitive); ok {
 			if len(spec) != 1 {
-				return fmt.Errorf("axis must hold a single expression, not %d", len(spec))
+				return posError(fmt.Errorf("axis must hold a single expression, not %d", len(spec)), sp)
 			}
 			d := derived{
 				lo: l.e,
 				ro: spec[0],
 				op: "⍂",
 			}
-			p.setLeft(1, item{e: &d, class: verb})
+			p.setLeft(1, item{e: &d, class: verb, pos: pos})
 			p.removeLeft(0)
 		} else if _, ok := l.e.(*derived); ok {
 			// The axis specification following an operator is rewritten as a dyadic operator.
 			// The operator is called "⍂" and as the left operand the axis spec is inserted.
 			if len(spec) != 1 {
-				return fmt.Errorf("axis must hold a single expression, not %d", len(spec))
+				return posError(fmt.Errorf("axis must hold a single expression, not %d", len(spec)), sp)
 			}
 			d := derived{
 				op: "⍂",
 			}
-			p.setLeft(1, item{e: &d, class: conjunction})
-			p.insertLeft(1, item{e: spec[0], class: noun})
+			p.setLeft(1, item{e: &d, class: conjunction, pos: sp})
+			p.insertLeft(1, item{e: spec[0], class: noun, pos: sp})
 		} else if l.class == noun {
 			// Axis specification follows an array or a noun expression.
 			fn := &function{
 				Function: Primitive("⌷"),
 				left:     spec,
 				right:    l.e,
+				pos:      sp,
 			}
-			p.setLeft(1, item{e: fn, class: noun})
+			p.setLeft(1, item{e: fn, class: noun, pos: pos})
 			p.removeLeft(0)
 			return nil
 		} else {
-			return fmt.Errorf("bracket expr following an %T %v\n", l.e, l.class)
+			return posError(fmt.Errorf("bracket expr following an %T %v\n", l.e, l.class), sp)
 		}
 	}
 	return nil
@@ -584,12 +821,15 @@ func (p *parser) mopReduce(i int) bool {
 		if (c0&op != 0) || ((i == 1) && (c1&op != 0)) {
 			return reduced
 		}
+		if isAssignOp(p.leftItem(i).e) {
+			return reduced
+		}
 		d := p.leftItem(i + 1).e.(*derived)
 		if d.lo != nil {
 			fmt.Println("dopReduce: overwriting LO")
 		}
 		d.lo = p.leftItem(i).e
-		p.setLeft(i, item{e: d, class: verb})
+		p.setLeft(i, item{e: d, class: verb, pos: p.leftItem(i).pos.join(p.leftItem(i + 1).pos)})
 		p.removeLeft(i + 1)
 		reduced = true
 		i++
@@ -610,6 +850,9 @@ func (p *parser) dopReduce(i int) bool {
 	if (c0&op != 0) || ((i == 1) && (c1&op != 0)) {
 		return false
 	}
+	if isAssignOp(p.leftItem(i).e) {
+		return false
+	}
 	d := p.leftItem(i + 1).e.(*derived)
 	if d.lo != nil {
 		fmt.Println("dopReduce: overwriting LO")
@@ -619,7 +862,8 @@ func (p *parser) dopReduce(i int) bool {
 	}
 	d.lo = p.leftItem(i).e
 	d.ro = p.leftItem(i + 2).e
-	p.setLeft(i, item{e: d, class: verb})
+	pos := p.leftItem(i).pos.join(p.leftItem(i + 1).pos).join(p.leftItem(i + 2).pos)
+	p.setLeft(i, item{e: d, class: verb, pos: pos})
 	p.removeLeft(i + 1)
 	p.removeLeft(i + 1)
 
@@ -673,8 +917,9 @@ func (p *parser) reducefA(last bool) bool {
 		fn := &function{
 			Function: f.e.(Function),
 			right:    r.e,
+			pos:      f.pos,
 		}
-		p.setRight(1, item{e: fn, class: noun})
+		p.setRight(1, item{e: fn, class: noun, pos: f.pos.join(r.pos)})
 		p.removeRight(0)
 		return true
 	}
@@ -705,8 +950,9 @@ func (p *parser) reduceAfA(last bool) bool {
 			Function: f.e.(Function),
 			left:     l.e,
 			right:    r.e,
+			pos:      f.pos,
 		}
-		p.setRight(2, item{e: fn, class: noun})
+		p.setRight(2, item{e: fn, class: noun, pos: l.pos.join(f.pos).join(r.pos)})
 		p.removeRight(0)
 		p.removeRight(0)
 	}
@@ -749,7 +995,7 @@ func (p *parser) resolveFunctions(last bool) {
 		r1 := p.rightItem(1)
 		if t, ok := r0.e.(train); ok && r1.class == noun && len(t)%2 == 0 {
 			t = append(train{r1.e}, t...)
-			p.setRight(1, item{e: t, class: verb})
+			p.setRight(1, item{e: t, class: verb, pos: r1.pos.join(r0.pos)})
 			p.removeRight(0)
 		}
 	}
@@ -767,12 +1013,13 @@ func (p *parser) reduceff(last bool) bool {
 		c = p.rightItem(2).class
 	}
 	if (r0.class == verb && r1.class == verb) && ((len(p.stack) == 2 && last) || c != conjunction) {
+		pos := r1.pos.join(r0.pos)
 		if t, ok := r0.e.(train); ok {
 			t = append(train{r1.e}, t...)
-			p.setRight(1, item{e: t, class: verb})
+			p.setRight(1, item{e: t, class: verb, pos: pos})
 		} else {
 			t = train{r1.e, r0.e}
-			p.setRight(1, item{e: t, class: verb})
+			p.setRight(1, item{e: t, class: verb, pos: pos})
 		}
 		p.removeRight(0)
 		return true
@@ -796,6 +1043,19 @@ func (p *parser) specialJot(i item) item {
 	return i
 }
 
+// isAssignOp returns true, if the expression is an assignment operator.
+// It can never be the operand of another operator.
+func isAssignOp(e expr) bool {
+	d, ok := e.(*derived)
+	return ok && d.op == "←"
+}
+
+// Sub returns a parser for a substatement.
+// It inherits the operand classes within a lambda operator.
+func (p *parser) sub(tokens []scan.Token) *parser {
+	return &parser{a: p.a, tokens: tokens, operands: p.operands, ops: p.ops, src: p.src}
+}
+
 // RemoveLeft removes item i from the left side of the stack.
 func (p *parser) removeLeft(l int) {
 	i := len(p.stack) - 1 - l
@@ -868,9 +1128,40 @@ func (p *parser) splitTokens(at scan.Type, inc, dec []scan.Type) [][]scan.Token
 	return l
 }
 
+// opAssignment returns the lambda operator, if the stack contains
+// an assignment of an operator without operands.
+func (p *parser) opAssignment() (expr, bool) {
+	if len(p.stack) != 2 || isAssignOp(p.rightItem(1).e) == false {
+		return nil, false
+	}
+	if d, ok := p.rightItem(0).e.(*derived); ok && d.dop != nil && d.lo == nil && d.ro == nil {
+		return d.dop, true
+	}
+	return nil, false
+}
+
 // LinkFuncAssign corrects a function assignment, that was parsed as a train.
 //	f ← +
+// It also links the assignment of a lambda operator, which is left unreduced.
+//	f ← {⍺⍺/⍵}
+// The name of an assigned lambda operator is recorded, such that following
+// statements parse it as an operator.
 func (p *parser) linkFuncAssign() {
+	if dop, ok := p.opAssignment(); ok {
+		p.recordOp(p.rightItem(1).e.(*derived).lo, p.rightItem(0).class)
+		pos := p.rightItem(1).pos
+		p.stack = []item{
+			item{
+				e: &function{
+					Function: p.rightItem(1).e.(*derived),
+					right:    dop,
+					pos:      pos,
+				},
+				class: verb,
+				pos:   pos.join(p.rightItem(0).pos),
+			},
+		}
+	}
 	if len(p.stack) != 1 {
 		return
 	}
@@ -881,19 +1172,31 @@ func (p *parser) linkFuncAssign() {
 	// with the second verb as the right argument.
 	if t, ok := p.stack[0].e.(train); ok && len(t) == 2 {
 		if d, ok := t[0].(*derived); ok && d.op == "←" {
+			p.recordOp(d.lo, 0)
+			pos := p.stack[0].pos
 			p.stack = []item{
 				item{
 					e: &function{
 						Function: d,
 						right:    t[1],
+						pos:      pos,
 					},
 					class: verb,
+					pos:   pos,
 				},
 			}
 		}
 	}
 }
 
+// recordOp records the class of a lambda operator assigned to the name e.
+// A class of 0 records a function assignment, that hides an operator of the same name.
+func (p *parser) recordOp(e expr, c class) {
+	if name, ok := e.(fnVar); ok && p.ops != nil {
+		p.ops[string(name)] = c
+	}
+}
+
 func (p *parser) printStack() {
 	for k, i := range p.stack {
 		fmt.Printf("#%d: %s %s\n", k, i.class.String(), i.e.String(p.a.Format))
@@ -108,6 +108,38 @@ func TestMultiline(t *testing.T) {
 	}
 }
 
+// TestPos tests the source positions of scan and parse errors.
+func TestPos(t *testing.T) {
+	testCases := []struct {
+		in    string
+		line  int
+		caret string
+	}{
+		{"1+$", 1, "1+$\n  ^"},
+		{"1 (2", 1, "1 (2\n  ^"},
+		{". 1", 1, ". 1\n^"},
+		{"{\n\t1+2 1x\n}", 2, "\t1+2 1x\n\t    ^^"},
+		{"{⍵\n+/[1;2]⍵}", 2, "+/[1;2]⍵}\n  ^^^^^"},
+	}
+
+	for i, tc := range testCases {
+		a := New(os.Stdout)
+		reg(a)
+
+		_, err := a.ParseLines(tc.in)
+		e, ok := err.(Error)
+		if ok == false {
+			t.Fatalf("[%d] %s: expected an Error, got %T: %v", i+1, tc.in, err, err)
+		}
+		if e.Pos.Line != tc.line {
+			t.Fatalf("[%d] %s: expected line %d, got %d", i+1, tc.in, tc.line, e.Pos.Line)
+		}
+		if got := e.Pos.Caret(); got != tc.caret {
+			t.Fatalf("[%d] %s:\nexpected:\n%s\ngot:\n%s", i+1, tc.in, tc.caret, got)
+		}
+	}
+}
+
 // For testing the parser we register just a couple of dummy primitives and two operators.
 func reg(a *Apl) {
 	for _, r := range "+-*!>" {
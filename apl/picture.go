@@ -0,0 +1,324 @@
+package apl
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// picture is the system function ⎕fmt.
+// It formats the columns of a matrix or a table with a picture for each column.
+//
+//	"#,##0.00" ⎕fmt R
+//	("0";"#,##0.00";"2006-01-02";) ⎕fmt R
+//
+// L is a single picture for all columns, or a list or string vector with a picture for each column.
+// For a table, L may also be a dict from column names to pictures.
+//
+// R may be a scalar, a vector, which is formatted as a single column, a matrix or a table.
+// The result of an array is a string matrix with the same shape, the result of a table is a string
+// that starts with a header line.
+// Each column is padded to a common width: real numbers are aligned to the right, other values to the left.
+//
+// A picture for a real number contains digit placeholders:
+//	0  a digit that is always printed
+//	#  an optional digit, leading and trailing zeros are suppressed
+//	,  a thousands separator, if it is part of the integer digits
+//	.  the decimal point, the number of placeholders after it is the number of decimals
+// Text before and after the placeholders is copied, e.g. "$#,##0.00" or "0.0 kg".
+// Negative numbers have a - in front of the digits.
+// NaN and infinite numbers are printed as NaN, ∞ and -∞ without the picture.
+// If the picture starts with +, positive numbers have a + sign.
+// If it starts with ( and ends with ), negative numbers are printed in parenthesis and
+// positive numbers are padded with a space at both ends.
+//
+// For all other values, such as times, strings or complex numbers, the picture is used
+// as the format string of the value's type, e.g. a go time layout "2006-01-02" or "%-8s".
+// A complex number requires a format string, e.g. "%.2fJ%.2f", a picture of digit placeholders is an error.
+// An empty picture formats a value with ⍕.
+// An empty array returns an empty string matrix.
+type picture struct{}
+
+func (p picture) String(f Format) string { return "⎕fmt" }
+func (p picture) Copy() Value            { return p }
+
+func (p picture) Call(a *Apl, L, R Value) (Value, error) {
+	if L == nil {
+		return nil, fmt.Errorf("⎕fmt: must be called dyadically")
+	}
+	if t, ok := R.(Table); ok {
+		return p.table(a, L, t)
+	}
+
+	var cols, rows int
+	var at func(i, k int) Value
+	if ar, ok := R.(Array); ok {
+		shape := ar.Shape()
+		switch len(shape) {
+		case 0:
+			rows, cols = 1, 1
+			if ar.Size() == 0 {
+				rows = 0
+			}
+			at = func(i, k int) Value { return ar.At(0) }
+		case 1:
+			rows, cols = shape[0], 1
+			at = func(i, k int) Value { return ar.At(i) }
+		case 2:
+			rows, cols = shape[0], shape[1]
+			at = func(i, k int) Value { return ar.At(i*cols + k) }
+		default:
+			return nil, fmt.Errorf("⎕fmt: rank error: R must be a table or have rank ≤ 2")
+		}
+	} else {
+		rows, cols = 1, 1
+		at = func(i, k int) Value { return R }
+	}
+	pics, err := pictures(L, nil, cols)
+	if err != nil {
+		return nil, err
+	}
+	res := StringArray{Dims: []int{rows, cols}, Strings: make([]string, rows*cols)}
+	for k := 0; k < cols; k++ {
+		column := make([]string, rows)
+		right := make([]bool, rows)
+		for i := range column {
+			column[i], right[i], err = formatPicture(a.Format, pics[k], at(i, k))
+			if err != nil {
+				return nil, err
+			}
+		}
+		alignColumn(column, right, 0)
+		for i, s := range column {
+			res.Strings[i*cols+k] = s
+		}
+	}
+	return res, nil
+}
+
+// table formats the columns of a table.
+// The header contains the column names.
+func (p picture) table(a *Apl, L Value, t Table) (Value, error) {
+	keys := t.Keys()
+	pics, err := pictures(L, keys, len(keys))
+	if err != nil {
+		return nil, err
+	}
+	lines := make([][]string, t.Rows+1)
+	for i := range lines {
+		lines[i] = make([]string, len(keys))
+	}
+	for k, key := range keys {
+		col, ok := t.At(key).(Array)
+		if ok == false {
+			return nil, fmt.Errorf("⎕fmt: table column is not an array: %T", t.At(key))
+		}
+		column := make([]string, t.Rows+1)
+		right := make([]bool, t.Rows+1)
+		column[0] = key.String(a.Format)
+		for i := 0; i < t.Rows; i++ {
+			column[i+1], right[i+1], err = formatPicture(a.Format, pics[k], col.At(i))
+			if err != nil {
+				return nil, err
+			}
+		}
+		alignColumn(column, right, 1)
+		for i, s := range column {
+			lines[i][k] = s
+		}
+	}
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(strings.TrimRight(strings.Join(l, " "), " "))
+		b.WriteString("\n")
+	}
+	return String(b.String()), nil
+}
+
+// pictures returns a picture for each of the n columns.
+// If keys is not nil, L may be a dict that maps column names to pictures.
+func pictures(L Value, keys []Value, n int) ([]string, error) {
+	pics := make([]string, n)
+	switch v := L.(type) {
+	case String:
+		for i := range pics {
+			pics[i] = string(v)
+		}
+		return pics, nil
+	case Object:
+		if keys == nil {
+			return nil, fmt.Errorf("⎕fmt: a dict as L requires a table")
+		}
+		for i, k := range keys {
+			if s, ok := v.At(k).(String); ok {
+				pics[i] = string(s)
+			}
+		}
+		return pics, nil
+	case Array:
+		if v.Size() != n {
+			return nil, fmt.Errorf("⎕fmt: length error: %d pictures for %d columns", v.Size(), n)
+		}
+		for i := range pics {
+			s, ok := v.At(i).(String)
+			if ok == false {
+				return nil, fmt.Errorf("⎕fmt: picture must be a string: %T", v.At(i))
+			}
+			pics[i] = string(s)
+		}
+		return pics, nil
+	}
+	return nil, fmt.Errorf("⎕fmt: L must be a string, a list of strings or a dict: %T", L)
+}
+
+// alignColumn pads the strings of a column to a common width.
+// Strings marked as right are aligned to the right.
+// The first skip strings, e.g. a table header, are aligned left.
+func alignColumn(column []string, right []bool, skip int) {
+	w := 0
+	for _, s := range column {
+		if n := utf8.RuneCountInString(s); n > w {
+			w = n
+		}
+	}
+	for i, s := range column {
+		p := strings.Repeat(" ", w-utf8.RuneCountInString(s))
+		if right[i] && i >= skip {
+			column[i] = p + s
+		} else {
+			column[i] = s + p
+		}
+	}
+}
+
+// formatPicture formats a single value with a picture.
+// It returns true, if the value is a real number, which is aligned to the right.
+func formatPicture(af Format, pic string, v Value) (string, bool, error) {
+	x, isReal := realNumber(v)
+	if pic == "" {
+		return v.String(af), isReal, nil
+	} else if isReal {
+		return numericPicture(pic, x), true, nil
+	} else if isComplex(v) && strings.IndexByte(pic, '%') < 0 {
+		return "", false, fmt.Errorf("⎕fmt: picture %q cannot format a complex number, use a format string such as %%.2fJ%%.2f", pic)
+	}
+	f := Format{PP: af.PP, Fmt: make(map[reflect.Type]string)}
+	for t, s := range af.Fmt {
+		f.Fmt[t] = s
+	}
+	f.Fmt[reflect.TypeOf(v)] = pic
+	return v.String(f), false, nil
+}
+
+// isComplex returns true, if v is a number with a real and an imaginary part.
+func isComplex(v Value) bool {
+	n, ok := v.(Number)
+	return ok && strings.IndexByte(n.String(Format{PP: -1}), 'J') > 0
+}
+
+// realNumber converts a number to float64.
+// The conversion uses the full precision string representation, which also covers
+// numbers of other towers, such as rationals "13r2".
+func realNumber(v Value) (float64, bool) {
+	n, ok := v.(Number)
+	if ok == false {
+		return 0, false
+	}
+	s := strings.Replace(n.String(Format{PP: -1}), "¯", "-", -1)
+	if i := strings.IndexByte(s, 'r'); i > 0 {
+		num, err1 := strconv.ParseFloat(s[:i], 64)
+		den, err2 := strconv.ParseFloat(s[i+1:], 64)
+		return num / den, err1 == nil && err2 == nil
+	}
+	x, err := strconv.ParseFloat(s, 64)
+	return x, err == nil
+}
+
+// numericPicture formats x with a picture of digit placeholders.
+func numericPicture(pic string, x float64) string {
+	first := strings.IndexAny(pic, "#0")
+	last := strings.LastIndexAny(pic, "#0")
+	if first < 0 {
+		return pic
+	} else if math.IsNaN(x) {
+		return "NaN"
+	} else if math.IsInf(x, 1) {
+		return "∞"
+	} else if math.IsInf(x, -1) {
+		return "-∞"
+	}
+	prefix, digits, suffix := pic[:first], pic[first:last+1], pic[last+1:]
+	if i := strings.LastIndexByte(prefix, '.'); i >= 0 && i == len(prefix)-1 {
+		// A leading decimal point belongs to the digits: ".00"
+		prefix, digits = prefix[:i], "."+digits
+	}
+
+	ipic, fpic := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		ipic, fpic = digits[:i], digits[i+1:]
+	}
+	group := strings.IndexByte(ipic, ',') >= 0
+	mindigits := strings.Count(ipic, "0")
+	decimals := strings.Count(fpic, "0") + strings.Count(fpic, "#")
+	required := len(strings.TrimRight(strings.Replace(fpic, ",", "", -1), "#"))
+
+	neg := x < 0
+	s := strconv.FormatFloat(roundHalfUp(math.Abs(x), decimals), 'f', decimals, 64)
+	ipart, fpart := s, ""
+	if decimals > 0 {
+		ipart, fpart = s[:len(s)-decimals-1], s[len(s)-decimals:]
+	}
+	for len(fpart) > required && fpart[len(fpart)-1] == '0' {
+		fpart = fpart[:len(fpart)-1]
+	}
+	ipart = strings.TrimLeft(ipart, "0")
+	for len(ipart) < mindigits {
+		ipart = "0" + ipart
+	}
+	if group {
+		var b strings.Builder
+		for i, c := range ipart {
+			if i > 0 && (len(ipart)-i)%3 == 0 {
+				b.WriteByte(',')
+			}
+			b.WriteRune(c)
+		}
+		ipart = b.String()
+	}
+	num := ipart
+	if fpart != "" {
+		num += "." + fpart
+	}
+	if neg && strings.Trim(num, "0.,") == "" {
+		neg = false // do not print -0.00
+	}
+
+	if strings.HasPrefix(prefix, "(") && strings.HasSuffix(suffix, ")") {
+		if neg {
+			return prefix + num + suffix
+		}
+		return " " + prefix[1:] + num + suffix[:len(suffix)-1] + " "
+	} else if strings.HasPrefix(prefix, "+") {
+		if neg {
+			return prefix[1:] + "-" + num + suffix
+		}
+		return prefix[1:] + "+" + num + suffix
+	} else if neg {
+		return prefix + "-" + num + suffix
+	}
+	return prefix + num + suffix
+}
+
+// roundHalfUp rounds a non-negative x to the number of decimals.
+// Halfway cases are rounded up, away from zero, as expected in reports:
+// 12.25 is rounded to 12.3, where FormatFloat would round to even.
+func roundHalfUp(x float64, decimals int) float64 {
+	p := math.Pow10(decimals)
+	if y := x * p; y < 1e15 {
+		return math.Round(y) / p
+	}
+	return x
+}
@@ -0,0 +1,98 @@
+package apl
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ktye/iv/apl/scan"
+)
+
+// Pos is the position of an expression in the source.
+// Start and End are byte offsets within the source line.
+//
+// File and Line are set for input that is read by a LineBuffer, e.g. by EvalFile.
+// Line numbers start at 1.
+type Pos struct {
+	File   string
+	Line   int
+	Source string
+	Start  int
+	End    int
+}
+
+func (p Pos) valid() bool {
+	return p.End > p.Start
+}
+
+// Caret returns the source line and a second line with carets below the expression.
+//	1+`x
+//	 ^
+// It returns an empty string, if the position is unknown.
+func (p Pos) Caret() string {
+	if p.valid() == false || p.End > len(p.Source) {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(p.Source)
+	b.WriteRune('\n')
+	for _, r := range p.Source[:p.Start] {
+		if r == '\t' {
+			b.WriteRune('\t')
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	b.WriteString(strings.Repeat("^", utf8.RuneCountInString(p.Source[p.Start:p.End])))
+	return b.String()
+}
+
+// join returns the span of two positions.
+// If they are on different lines, the first is returned.
+func (p Pos) join(q Pos) Pos {
+	if p.valid() == false {
+		return q
+	} else if q.valid() == false || p.Line != q.Line || p.File != q.File {
+		return p
+	}
+	if q.Start < p.Start {
+		p.Start = q.Start
+	}
+	if q.End > p.End {
+		p.End = q.End
+	}
+	return p
+}
+
+// source contains the lines of a statement that is parsed.
+// Tokens are numbered by their line.
+type source struct {
+	file  string
+	first int
+	lines []string
+}
+
+// pos returns the position of a token.
+// Tokens that have been inserted by scan commands have no position.
+func (src *source) pos(t scan.Token) Pos {
+	if src == nil || t.End == 0 {
+		return Pos{}
+	}
+	n := t.Line - src.first
+	if n < 0 || n >= len(src.lines) {
+		return Pos{}
+	}
+	return Pos{File: src.file, Line: t.Line, Source: src.lines[n], Start: t.Pos, End: t.End}
+}
+
+// posError returns an Error at the given position.
+// If err is already an Error with a position, it is returned unchanged.
+func posError(err error, p Pos) error {
+	e, ok := err.(Error)
+	if ok == false {
+		e = Error{E: err}
+	}
+	if e.Pos.valid() == false {
+		e.Pos = p
+	}
+	return e
+}
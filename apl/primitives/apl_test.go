@@ -12,6 +12,7 @@ import (
 	"github.com/ktye/iv/apl/numbers"
 	"github.com/ktye/iv/apl/operators"
 	aplstrings "github.com/ktye/iv/apl/strings"
+	"github.com/ktye/iv/apl/tables"
 	"github.com/ktye/iv/apl/xgo"
 )
 
@@ -59,7 +60,25 @@ var testCases = []struct {
 	{"2×1 2 3=4 2 1", "0 2 0", 0},             // dyadic array
 	{"-3<4", "¯1", 0},                         // monadic scalar
 	{"-1 2 3=0 2 3", "0 ¯1 ¯1", 0},            // monadic array
-	{"⍝ TODO Comparison tolerance is not implemented.", "", 0},
+
+	{"⍝ Comparison tolerance", "apl/tolerance.go", 0},
+	{"0.3=0.1+0.2", "1", float},         // tolerant equality
+	{"⎕CT←0⋄0.3=0.1+0.2", "0", small},   // exact comparison
+	{"(0.1+0.2)∊0.3 0.4", "1", float},   // membership
+	{"0.3 0.4⍳0.1+0.2", "1", float},     // index of
+	{"∪0.3,0.1+0.2", "0.3", float},      // unique
+	{"0.3≡0.1+0.2", "1", float},         // match
+	{"(0.1+0.2)<0.3", "0", float},       // tolerant less
+	{"(0.1+0.2)≤0.3", "1", float},       // tolerant less or equal
+	{"⌊3×(1÷3)", "1", small},            // tolerant floor
+	{"⎕CT←0⋄⌊1-1E¯15", "0", small},      // exact floor
+	{"⌊1-1E¯15", "1", small},            // tolerant floor
+	{"⌈1+1E¯15", "1", small},            // tolerant ceil
+	{"⎕CT←1E¯10⋄⎕CT", "1E¯10", small},   // set ⎕CT
+	{"⎕CT←1E¯10⋄1=1+1E¯11", "1", small}, // larger tolerance
+	{"⎕CT←¯1", "fail: cannot set ⎕CT: must be a number in [0, 1)", 0},
+	{"1J1=1J1.00000000000001", "1", small}, // complex
+	{"⎕DCT", "1E¯28", small},               // ⎕DCT is used by the precise tower
 
 	{"⍝ Boolean, logical", "apl/primitives/boolean.go", 0},
 	{"0 1 0 1 ^ 0 0 1 1", "0 0 0 1", 0}, // and
@@ -191,6 +210,39 @@ var testCases = []struct {
 	{"1 2 3∪5 3 2 1 4", "1 2 3 5 4", 0},
 	{"5 6 7∪1 2 3", "5 6 7 1 2 3", 0},
 
+	{"⍝ Intersection, unique mask", "apl/primitives/unique.go", 0},
+	{"1 2 3 4∩3 1 5", "1 3", 0},
+	{"1 1 2 3∩3 1", "1 1 3", 0},
+	{"'abcab'∩'bx'", "b b", 0},
+	{"`a`b`c ∩ `c`a`d", "a c", 0},
+	{"⍴1 2∩⍳0", "0", 0},
+	{"⍴(⍳0)∩1 2", "0", 0},
+	{"≠3 1 3 2 1", "1 1 0 1 0", 0},
+	{"≠'mississippi'", "1 1 1 0 0 0 0 0 1 0 0", 0},
+	{"⍴≠⍳0", "0", 0},
+	{"(≠A)/A←4 5 4 6", "4 5 6", 0},
+	{"∪(1;2 3;1;2 3;)", "(1;2 3;)", 0},
+	{"≠(1;2 3;1;2 3;)", "1 1 0 0", 0},
+	{"(1;2 3;1;)∩(2 3;5;)", "(2 3;)", 0},
+	{"(1;2 3;1;)~(2 3;5;)", "(1;1;)", 0},
+	{"(1;2 3;)∪(2 3;4;)", "(1;2 3;4;)", 0},
+	{"∪3 2⍴1 2 3 4 1 2", "1 2\n3 4", 0},
+	{"≠3 2⍴1 2 3 4 1 2", "1 1 0", 0},
+	{"(3 2⍴1 2 3 4 1 2)∩2 2⍴1 2 5 6", "1 2\n1 2", 0},
+	{"(3 2⍴1 2 3 4 1 2)~2 2⍴1 2 5 6", "3 4", 0},
+	{"(2 2⍴1 2 3 4)∪2 2⍴5 6 1 2", "1 2\n3 4\n5 6", 0},
+	{"(2 3⍴⍳6)∩2 2⍴1", "fail: length error: matrices have 3 and 2 columns", 0},
+	{"T←⍉`a`b#(1 2 1;`x`y`x;)⋄∪T", "a b\n1 x\n2 y", small},
+	{"T←⍉`a`b#(1 2 1;`x`y`x;)⋄≠T", "1 1 0", small},
+	{"T←⍉`a`b#(1 2 1;`x`y`x;)⋄T∩⍉`a`b#(1 3;`x`z;)", "a b\n1 x\n1 x", small},
+	{"T←⍉`a`b#(1 2 1;`x`y`x;)⋄T~⍉`b`a#(`x`z;1 3;)", "a b\n2 y", small},
+	{"T←⍉`a`b#(1 2;`x`y;)⋄T∪⍉`a`b#(1 3;`x`z;)", "a b\n1 x\n2 y\n3 z", small},
+	{"T←⍉`a`b#(1 2;`x`y;)⋄T∩⍉`a`c#(1 3;`x`z;)", "fail: set function: tables have different columns", small},
+	{"D←`a`b`c#1 2 3⋄D∪`c`d#7 8", "a: 1\nb: 2\nc: 3\nd: 8", 0},
+	{"D←`a`b`c#1 2 3⋄D∩`c`d#7 8", "c: 3", 0},
+	{"D←`a`b`c#1 2 3⋄D~`a`c", "b: 2", 0},
+	{"D←`a`b`c#1 2 3⋄D~`c`d#7 8", "a: 1\nb: 2", 0},
+
 	{"⍝ Find", "apl/primitives/find.go", 0},
 	{"'AN'⍷'BANANA'", "0 1 0 1 0 0", 0},
 	{"'ANA'⍷'BANANA'", "0 1 0 1 0 0", 0},
@@ -553,19 +605,29 @@ var testCases = []struct {
 
 	{"⍝ Format as a string, Execute", "apl/primitives/format.go", 0},
 
-	{"⍕10", "10", 0},                                  // format as string
-	{"⍕10.1", "10.1", small},                          // format as string
-	{"⍕123.45678901234", "123.457", small},            // format as string
-	{"4⍕123.45678901234", "123.5", small},             // format with precision
-	{"`%.3f@%.1f ⍕1J2", "2.236@63.4", small},          // format with string
-	{"`%.3f ⍕¯1.23456", "¯1.235", small},              // format with string
-	{"`-%.3f ⍕¯1.23456", "-1.235", small},             // format with string (normal minus sign)
-	{`⍕"alpha"`, `alpha`, 0},                          // format with default stringer
-	{`¯1⍕"alpha"`, `"alpha"`, 0},                      // format with text marshaler
-	{`¯1⍕"al\npha"`, `"al\npha"`, 0},                  // format with text marshaler
-	{"`csv ⍕2 3⍴⍳6", "1,2,3\n4,5,6", 0},               // format as csv
-	{"`csv ⍕2 2⍴`a`b`c\"t`d", "a,b\n\"c\"\"t\",d", 0}, // format as csv
-	{`⍎"1+1"`, "2", 0},                                // evaluate expression
+	{"⍕10", "10", 0},                                              // format as string
+	{"⍕10.1", "10.1", small},                                      // format as string
+	{"⍕123.45678901234", "123.457", small},                        // format as string
+	{"4⍕123.45678901234", "123.5", small},                         // format with precision
+	{"`%.3f@%.1f ⍕1J2", "2.236@63.4", small},                      // format with string
+	{"`%.3f ⍕¯1.23456", "¯1.235", small},                          // format with string
+	{"`-%.3f ⍕¯1.23456", "-1.235", small},                         // format with string (normal minus sign)
+	{`⍕"alpha"`, `alpha`, 0},                                      // format with default stringer
+	{`¯1⍕"alpha"`, `"alpha"`, 0},                                  // format with text marshaler
+	{`¯1⍕"al\npha"`, `"al\npha"`, 0},                              // format with text marshaler
+	{"`csv ⍕2 3⍴⍳6", "1,2,3\n4,5,6", 0},                           // format as csv
+	{"`csv ⍕2 2⍴`a`b`c\"t`d", "a,b\n\"c\"\"t\",d", 0},             // format as csv
+	{`⍎"1+1"`, "2", 0},                                            // evaluate expression
+	{"T←⍉`a`b#(1 2;`x`y;)⋄\"T\"⍎`csv ⍕T", "a b\n1 x\n2 y", 0},     // parse a csv table
+	{"T←⍉`a`b#(1 2;`x`y;)⋄(¯1⍕T)≡¯1⍕\"T\"⍎¯1⍕T", "1", 0},          // parse a table formatted with ¯1⍕
+	{"\"T\"⍎\"1,2\\n3,4\"", "C1 C2\n1 2\n3 4", 0},                 // without header
+	{"\"T\"⍎\"a\tb\\n\\\"1\\\"\t1.5\"", "a b\n1 1.5", small},      // tsv, quoted strings
+	{"P←⍉`a`b#(1 2;1.5 2;)⋄⍴P⍎\"b,a\\n1,2\\n3,4\"", "2 2", small}, // prototype
+	{"P←⍉`a`b#(1 2;1.5 2;)⋄P⍎\"a,b\\n1,2\\nx,4\"", "fail: parse table: line 3 column 1: not a number: \"x\"", small},
+	{"P←⍉`a`b#(1 2;1.5 2;)⋄P⍎\"a,b\\n1.5,2\"", "fail: parse table: line 2 column 1: cannot convert \"1.5\" to apl.Int", small},
+	{"P←⍉`a`b#(1 2;1.5 2;)⋄{0::⎕DMX[`message]⋄P⍎⍵}\"a,b\\n\\n1,2\\n\\nx,4\"", "parse table: line 5 column 1: not a number: \"x\"", small},
+	{"\"T\"⍎\"year,2019\\n2020,3\"", "C1 C2\nyear 2019\n2020 3", 0},                         // a header with a number is a data row
+	{"P←⍉(`year;`2019;)#(2019 2020;1 2;)⋄P⍎\"year,2019\\n2020,3\"", "year 2019\n2020 3", 0}, // the prototype forces the header
 	{"⍝ TODO: dyadic format with specification.", "", 0},
 	{"⍝ TODO: dyadic execute with namespace.", "", 0},
 
@@ -578,7 +640,21 @@ var testCases = []struct {
 	{"⍋'alpha'", "1 5 4 2 3", 0},                                    // strings grade up
 	{"'ABCDE'⍒'BEAD'", "2 4 1 3", 0},                                // grade down with collating sequence
 	{"⍝ TODO dyadic grade up/down is only implemented for vector L", "", 0},
-	{"A←23 11 13 31 12⋄A[⍋A]", "11 12 13 23 31", 0}, // sort
+	{"A←23 11 13 31 12⋄A[⍋A]", "11 12 13 23 31", 0},                                            // sort
+	{"T←⍉`Sym`Qty`Px#(`b`a`b`a`c;3 8 3 9 2;10 20 30 40 50;)⋄⍋T", "2 4 1 3 5", 0},               // grade table rows by all columns
+	{"T←⍉`Sym`Qty`Px#(`b`a`b`a`c;3 8 3 9 2;10 20 30 40 50;)⋄⍒T", "5 3 1 4 2", 0},               // grade down table rows
+	{"T←⍉`Sym`Qty`Px#(`b`a`b`a`c;3 8 3 9 2;10 20 30 40 50;)⋄`Sym ⍋T", "2 4 1 3 5", 0},          // grade by a single column, stable
+	{"T←⍉`Sym`Qty`Px#(`b`a`b`a`c;3 8 3 9 2;10 20 30 40 50;)⋄`Sym ⍒T", "5 1 3 2 4", 0},          // grade down by a single column, stable
+	{"T←⍉`Sym`Qty`Px#(`b`a`b`a`c;3 8 3 9 2;10 20 30 40 50;)⋄(`Sym`Px#1 ¯1)⍋T", "4 2 3 1 5", 0}, // mixed directions
+	{"T←⍉`Sym`Qty`Px#(`b`a`b`a`c;3 8 3 9 2;10 20 30 40 50;)⋄T[(`Sym`Px#1 ¯1)⍋T]", "Sym Qty Px\na 9 40\na 8 20\nb 3 30\nb 3 10\nc 2 50", 0}, // sort table rows
+	{"T←⍉`Sym`Qty`Px#(`b`a`b`a`c;3 8 3 9 2;10 20 30 40 50;)⋄`X ⍋T", "fail: grade: column does not exist: X", 0},
+	{"D←`Sym`Qty#(`b`a`b;3 1 2;)⋄(⍉D)[`Sym`Qty ⍋D]", "Sym Qty\na 1\nb 2\nb 3", 0}, // sort a dict
+	{"D←`a`b#(1 2;3;)⋄⍋D", "fail: grade: dict values must be vectors of the same length", 0},
+	{"⍋3,(¯1○2),1", "2 3 1", small}, // NaN is less than all numbers
+	{"⍒3,(¯1○2),1", "1 3 2", small},
+	{"⍒23 14 23 12 14", "1 3 2 5 4", 0}, // stable
+	{"T←⍉`Time`V#(2018.12.23T10.00.02 2018.12.23T10.00.01 2018.12.23T10.00.02;1 2 3;)⋄T[(`V#¯1)⍋T]", "Time V\n2018.12.23T10.00.02.000 3\n2018.12.23T10.00.01.000 2\n2018.12.23T10.00.02.000 1", small},
+	{"T←⍉`Time`V#(2018.12.23T10.00.02 2018.12.23T10.00.01 2018.12.23T10.00.02;1 2 3;)⋄⍒T[;1⍴`Time]", "1 3 2", small},
 
 	{"⍝ Reverse, revere first", "apl/primitives/reverse.go", 0},
 	{"⌽1 2 3 4 5", "5 4 3 2 1", 0}, // reverse vector
@@ -629,6 +705,21 @@ var testCases = []struct {
 	{`⍴','⊃",a,,b,c"`, "5", 0},
 	{`⍴""⊃" a  b c\tc "`, "4", 0},
 
+	{"⍝ Partition, partitioned enclose", "apl/primitives/partition.go", 0},
+	{"1 1 2 2 0 3⊆⍳6", "(1 2;3 4;6;)", 0},
+	{"1 0 1 0 0⊂⍳5", "(1 2;3 4 5;)", 0},
+	{"1⊂⍳3", "(1;2;3;)", 0},
+	{"0 1 0 1 0⊂⍳5", "(2 3;4 5;)", 0},
+	{"1 0 1 0 0⊂2 3 4 5 6.5", "(2 3;4 5 6.5;)", small},
+	{`1 1 2⊆"x" "y" "z"`, "(x y;z;)", 0},
+	{"1 0 1⊂(1;2;3;)", "((1;2;);(3;);)", 0},
+	{`(" "≠⊃S)⊆S←"alpha beta  gamma"`, "(alpha;beta;gamma;)", 0},
+	{`(","=⊃S)⊂S←",a,bc,,d"`, "(,a;,bc;,;,d;)", 0},
+	{"1 2⊆⍳3", "fail: partition: length error: 2 != 3", 0},
+	{"(<¨1 0 1 0 0)⊂<¨⍳5", "1 2\n3 4 5", 0},
+	{"(<¨1 1 0 2 2)⊆<¨⍳5", "1 2\n4 5", 0},
+	{"1 0 1 0⊂⍳4", "┌───┬───┐\n│1 2│3 4│\n└───┴───┘", nested},
+
 	{"⍝ Domino, solve linear system", "apl/primitives/domino.go", 0},
 	{"⌹2 2⍴2 0 0 1", "0.5 0\n0 1", small},
 	// TODO: this fails for big.Float. Remove sfloat and debug
@@ -748,7 +839,52 @@ var testCases = []struct {
 	// TODO: 1+∘÷⍣=1 oscillates for big.Float.
 	// TODO: Add comparison tolerance and remove sfloat.
 	{"1+∘÷⍣=1", "1.61803", small}, // fixed point iteration golden ratio
-	{"⍝ TODO: function inverse", "", 0},
+
+	{"⍝ Function inverse", "apl/inverse.go", 0},
+	{"3(+⍣¯1)5", "2", 0},
+	{"(-⍣¯1)4", "¯4", 0},
+	{"2(×⍣¯1)8", "4", 0},
+	{"(÷⍣¯1)4", "0.25", float},
+	{"2(*⍣¯1)8", "3", float},
+	{"10(⍟⍣¯1)2", "100", float},
+	{"1(○⍣¯1)1○0.5", "0.5", small},
+	{"10 10 10(⊤⍣¯1)1 2 3", "123", 0},
+	{"10 10 10(⊥⍣¯1)123", "1 2 3", 0},
+	{"(2∘⊥)⍣¯1⊢5", "1 0 1", 0}, // a scalar radix is extended
+	{"2⊥(2∘⊥)⍣¯1⊢5", "5", 0},
+	{"10⊥10(⊥⍣¯1)1000", "1000", 0},
+	{"2(⊥⍣¯1)5 3", "1 0\n0 1\n1 1", 0},
+	{"24 60 60⊥24 60 60(⊥⍣¯1)3723", "3723", 0}, // mixed radix
+	{"(⌽⍣¯1)1 2 3", "3 2 1", 0},
+	{"1(⌽⍣¯1)1 2 3", "3 1 2", 0},
+	{"1(⊖⍣¯1)1 2 3", "3 1 2", 0},
+	{"X←2 3 4⍴⍳24⋄X≡2 3 1(⍉⍣¯1)2 3 1⍉X", "1", 0},
+	{"2 2(⍉⍣¯1)2 2⍴⍳4", "fail: transpose: only a permutation of the axes has an inverse", 0},
+	{"(⍉⍣¯1)`a`b#(1 2;3 4;)", "a b\n1 3\n2 4", small},
+	{"(+\\⍣¯1)1 3 6 10", "1 2 3 4", 0},
+	{"(+\\⍣¯1)+\\2 3⍴⍳6", "1 2 3\n4 5 6", 0},
+	{"(+⍀⍣¯1)+⍀2 3⍴⍳6", "1 2 3\n4 5 6", 0},
+	{"(2∘×)⍣¯1⊢10", "5", 0},
+	{"(-∘2)⍣¯1⊢10", "12", 0},
+	{"(*∘2)⍣¯1⊢9", "3", small},
+	{"(*∘2)(*∘2)⍣¯1⊢16", "16", small},
+	{"(⍟∘8)⍣¯1⊢3", "2", small},
+	{"(⍟∘8)(⍟∘8)⍣¯1⊢3", "3", small},
+	{"f←2∘×⋄(f⍣¯1)10", "5", 0},
+	{"(-÷)⍣¯1⊢4", "¯0.25", float},
+	{"((3+*)⍣¯1)4", "0", float},
+	{"(-⍣¯2)3", "3", 0},
+	{"((1∘+)⍣3)⍣¯1⊢10", "7", 0},
+	{"({⍵+1}⍣¯1)3", "fail: function has no inverse: {(⍵ + 1)}", 0},
+	{"(|⍣¯1)3", "fail: primitive | has no inverse", 0},
+	{"(+/⍣¯1)3", "fail: function has no inverse: (+ /)", 0},
+
+	{"⍝ Under, dual", "apl/operators/under.go", 0},
+	{"2 +⍢⍟ 3", "6", float},
+	{"⌽⍢⍉ 2 3⍴⍳6", "4 5 6\n1 2 3", 0},
+	{"(×∘2)⍢(+∘1) 4", "9", 0},
+	{"((×∘2)⍢(+∘1)⍣¯1) 9", "4", 0},
+	{"{⍵}⍢{⍵} 1", "fail: function has no inverse: {⍵}", 0},
 
 	{"⍝ Rank operator", "apl/operators/rank.go", 0},
 	{`+\⍤0 +2 3⍴1`, "1 1 1\n1 1 1", 0},
@@ -775,6 +911,121 @@ var testCases = []struct {
 	{"⍝ Stencil", "apl/operators/stencil.go", 0},
 	{"{⌈/⌈/⍵}⌺(3 3) ⊢3 3⍴⍳25", "5 6 6\n8 9 9\n8 9 9", 0},
 
+	{"⍝ Key, group by", "apl/operators/key.go", 0},
+	{"{⍺,≢⍵}⌸3 1 3 3", "3 3\n1 1", 0},
+	{"{⍵}⌸3 1 3 3", "1 3 4\n2 0 0", 0},
+	{"{≢⍵}⌸`a`b`a`c`a", "3 1 1", 0},
+	{"{≢⍵}⌸1.5 2 1.5 2.25", "2 1 1", small},
+	{"{≢⍵}⌸1b 1 0 2", "2 1 1", 0},
+	{"{⍺}⌸2 2⍴1 2 1 2", "1 2", 0},
+	{"1 2 1 {+/⍵}⌸10 20 30", "40 20", 0},
+	{"`x`y`x {+⌿⍵}⌸3 2⍴⍳6", "6 8\n3 4", 0},
+	{"{≢⍵}⌸(1;'a';1;)", "2 1", 0},
+	{"1 2 {⍵}⌸(1;'a';)", "((1;);(a;);)", 0},
+	{"1 2{⍵}⌸1 2 3", "fail: key: length error: 2 != 3", 0},
+	{"T←⍉`s`q#(`a`b`a;1 2 3;)⋄`s {+/⍵}⌸T", "s q\na 4\nb 2", small},
+	{"T←⍉`s`q#(`a`b`a;1 2 3;)⋄{+/⍵}⌸T", "s q\na 4\nb 2", small},
+	{"T←⍉`s`q#(`a`b`a;1 2 3;)⋄`s {`n`m#(≢⍵;⌈/⍵[`q];)}⌸T", "s n m\na 2 3\nb 1 2", small},
+	{"T←⍉`s`t`q#(`a`b`a;1 1 1;1 2 3;)⋄`s`t {+/⍵}⌸T", "s t q\na 1 4\nb 1 2", small},
+	{"T←⍉`s`q#(`a`b`a;1 2 3;)⋄`s {≢⍵}⌸T", "fail: key: table function must return a table or a dict: apl.Int", small},
+
+	{"⍝ Multiset intersection, without, union", "apl/operators/multiset.go", 0},
+	{"1 1 1 2∩⍦1 1 3", "1 1", 0},
+	{"1 1 1 2~⍦1 1 3", "1 2", 0},
+	{"1 1 1 2∪⍦1 1 3", "1 1 1 2 3", 0},
+	{"'mississippi'~⍦'miss'", "i s s i p p i", 0},
+	{"(1;1;2;)∩⍦(1;3;)", "(1;)", 0},
+	{"(1;1;2;)∪⍦(1;3;1;1;)", "(1;1;2;3;1;)", 0},
+	{"(3 2⍴1 2 1 2 3 4)~⍦1 2⍴1 2", "1 2\n3 4", 0},
+	{"∩⍦1 2", "fail: multiset: derived function ∩⍦ must be called dyadically", 0},
+
+	{"⍝ Variant, options", "apl/operators/variant.go", 0},
+	{"⍋⍠(`collation#'cab')'abcab'", "3 1 4 2 5", 0},
+	{"⍒⍠(`collation#'cab')'abcab'", "2 5 1 4 3", 0},
+	{"{⍋⍵}⍠(`collation#'cab')'abcab'", "1 4 2 5 3", 0}, // options are not visible in the lambda body
+	{"(⍋⍠(`collation#'cab'))⍠(`PP#3)'abcab'", "3 1 4 2 5", 0},
+	{"{⍋⍵}⍣1⍠(`collation#'cab')⊢'abcab'", "1 4 2 5 3", 0},
+	{"⍕⍠(`PP#3)○1", "3.14", small},
+	{"⍕⍠(`mode#`json)1 2", "[1,2]", 0},
+	{"⍕⍠(`mode`PP#(`csv;3;))2 2⍴1 2 3 4", "1,2\n3,4", 0},
+	{"{⍵+1}⍣{⍺>20}⍠(`limit#10)⊢0", "fail: power: recusion limit exceeded", 0},
+	{"{⍵+1}⍣{⍺>5}⍠(`limit#10)⊢0", "6", 0},
+	{"j←go→join⍠(`sep#'-')⋄j `a`b`c", "a-b-c", 0},
+	{"j←go→join⋄j `a`b`c", "a b c", 0},
+
+	{"⍝ Page width and height", "apl/page.go", 0},
+	{"⎕PW←20⋄⎕PW", "20", 0},
+	{"⎕PW←20⋄⍳12", "1 2 3 4 5 6 7 8 9 10\n11 12", 0},
+	{"⎕PW←20⋄⎕PH←2⋄⍳100", "1 2 3 4 5 6 7 8 9 10\n… ⍴100", 0},
+	{"⎕PW←12⋄⎕PH←3⋄100 100⍴⍳10000", "1 2 …\n101 102 …\n… ⍴100 100", 0},
+	{"⎕PH←4⋄⍉`a`b#(⍳5;5⍴`x;)", "a b\n1 x\n2 x\n… ⍴5 2", 0},
+	{"⎕PW←0⋄⎕PH←3⋄⍳1000", "1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30\n31 32 33 34 35 36 37 38 39 40 41 42 43 44 45 46 47 48 49 50 51 52 53 54 55\n… ⍴1000", 0}, // default page width
+	{"⍕⍠(`PW#20)⍳12", "1 2 3 4 5 6 7 8 9 10\n11 12", 0},
+	{"⎕PW←¯1", "fail: cannot set ⎕PW: must be a non-negative integer", 0},
+
+	{"⍝ Picture format ⎕fmt", "apl/picture.go", 0},
+	{"\"#,##0.00\" ⎕fmt 1234.5 ¯7", "1,234.50\n-7.00", 0},
+	{"(\"000\";\"(#,##0)\";) ⎕fmt 2 2⍴1 ¯1500 12 3000", "001 (1,500)\n012 3,000", 0},
+	{"⍴\"0\" ⎕fmt 2 3⍴⍳6", "2 3", 0},
+	{"(`b#\"0.0\") ⎕fmt ⍉`a`b#(`x`yy;1.5 ¯2.5;)", "a b\nx 1.5\nyy -2.5", 0},
+	{"\"%-3s|\" ⎕fmt `a`b", "a |\nb |", 0},
+	{"\"0.0 kg\" ⎕fmt 12.25", "12.3 kg", small}, // round half away from zero
+	{"\"0.000\" ⎕fmt (*1000)-*1000", "NaN", small},
+	{"\"0.00\" ⎕fmt (*1000),-*1000", "∞\n-∞", small},
+	{"⍴\"0.0\" ⎕fmt ⍳0", "0 1", 0},
+	{"⍴\"0.0\" ⎕fmt 0 3⍴0", "0 3", 0},
+	{"\"0.00\" ⎕fmt 3J4", "fail: ⎕fmt: picture \"0.00\" cannot format a complex number", small},
+	{"\"%.1fJ%.1f\" ⎕fmt 3J4", "3.0J4.0", small},
+	{"(\"0\";\"0\";) ⎕fmt 1 2 3", "fail: ⎕fmt: length error: 2 pictures for 1 columns", 0},
+
+	{"⍝ Relational joins", "apl/tables/join.go", 0},
+	{"L←⍉`id`x#(1 2 3;`a`b`c;)⋄R←⍉`id`y#(2 3 3 4;10 20 30 40;)⋄L t→join R", "id x y\n2 b 10\n3 c 20\n3 c 30", 0},
+	{"L←⍉`id`x#(1 2 3;`a`b`c;)⋄R←⍉`id`y#(2 3 3 4;10 20 30 40;)⋄L t→ljoin R", "id x y\n1 a 0\n2 b 10\n3 c 20\n3 c 30", 0},
+	{"L←⍉`id`x#(1 2 3;`a`b`c;)⋄R←⍉`id`y#(2 3 3 4;10 20 30 40;)⋄⍴L t→ojoin R", "5 3", 0},
+	{"L←⍉`id`x#(1 2 3;`a`b`c;)⋄R←⍉`id`y#(2 3 3 4;10 20 30 40;)⋄J←L t→ojoin R⋄J[`y]", "0 10 20 30 40", 0},
+	{"L←⍉`id`x#(1 2 3;`a`b`c;)⋄R←⍉`id`y#(2 3 3 4;10 20 30 40;)⋄⍴L t→ujoin R", "7 3", 0},
+	{"L←⍉`id`x#(1 2 3;`a`b`c;)⋄R←⍉`id`y#(2 3 3 4;10 20 30 40;)⋄L t→join⍠(`on#`id) R", "id x y\n2 b 10\n3 c 20\n3 c 30", 0},
+	{"L←⍉`k`v#(1 2;5 6;)⋄R←⍉`k`v#(2 3;7 8;)⋄L t→ljoin⍠(`on#`k) R", "k v\n1 5\n2 7", 0},
+	{"L←⍉`k`v#(1 2;5 6;)⋄R←⍉`k`w#(`a`b;7 8;)⋄L t→join⍠(`on#`k) R", "fail: join: key column k has different types: apl.Int and apl.String", 0},
+	{"L←⍉`k`v#(1 2;5 6;)⋄R←⍉`w#⊂1 2⋄L t→join R", "fail: join: tables have no common columns", 0},
+
+	{"⍝ As-of and window joins", "apl/tables/asof.go", 0},
+	{"T←⍉`sym`time`qty#(`a`b`a;2018.12.23T10.00.02 2018.12.23T10.00.03 2018.12.23T10.00.05;1 2 3;)⋄Q←⍉`sym`time`bid#(`a`b`a`a;2018.12.23T10.00.01 2018.12.23T10.00.04 2018.12.23T10.00.03 2018.12.23T10.00.05;10 20 30 40;)⋄J←T t→aj⍠(`on#`sym`time) Q⋄J[`bid]", "10 0 40", small},
+	{"T←⍉`sym`time`qty#(`a`b`a;2018.12.23T10.00.02 2018.12.23T10.00.03 2018.12.23T10.00.05;1 2 3;)⋄Q←⍉`sym`time`bid#(`a`b`a`a;2018.12.23T10.00.01 2018.12.23T10.00.04 2018.12.23T10.00.03 2018.12.23T10.00.05;10 20 30 40;)⋄J←T t→aj⍠(`on#`sym`time) Q⋄⍴J", "3 4", small},
+	{"T←⍉`sym`time`qty#(`a`b`a;2018.12.23T10.00.02 2018.12.23T10.00.03 2018.12.23T10.00.05;1 2 3;)⋄Q←⍉`sym`time`bid#(`a`b`a`a;2018.12.23T10.00.01 2018.12.23T10.00.04 2018.12.23T10.00.03 2018.12.23T10.00.05;10 20 30 40;)⋄J←T t→aj⍠(`on#`time) Q⋄J[`bid]", "10 30 40", small},
+	{"T←⍉`sym`time`qty#(`a`b`a;2018.12.23T10.00.02 2018.12.23T10.00.03 2018.12.23T10.00.05;1 2 3;)⋄Q←⍉`sym`time`bid#(`a`b`a`a;2018.12.23T10.00.01 2018.12.23T10.00.04 2018.12.23T10.00.03 2018.12.23T10.00.05;10 20 30 40;)⋄J←T t→aj⍠(`on#`time) Q⋄J[`sym]", "a a a", small},
+	{"T←⍉`sym`time`qty#(`a`a;2018.12.23T10.00.02 2018.12.23T10.00.05;1 2;)⋄Q←⍉`sym`time`bid#(`a`a`a;2018.12.23T10.00.01 2018.12.23T10.00.01 2018.12.23T10.00.01;10 20 30;)⋄J←T t→aj⍠(`on#`sym`time) Q⋄J[`bid]", "30 30", small}, // ties keep their order
+	{"T←⍉`time`qty#(1.2 2.2 3.2;4 5 6;)⋄Q←⍉`time`bid#(2.5 0.5 1.5;10 20 30;)⋄J←T t→aj⍠(`on#`time) Q⋄J[`bid]", "20 30 10", small},
+	{"T←⍉`sym`time`qty#(`a`b`a;2018.12.23T10.00.02 2018.12.23T10.00.03 2018.12.23T10.00.05;1 2 3;)⋄Q←⍉`sym`time`bid#(`a`b`a`a;2018.12.23T10.00.01 2018.12.23T10.00.04 2018.12.23T10.00.03 2018.12.23T10.00.05;10 20 30 40;)⋄J←T t→wj⍠(`on`window`agg#(`sym`time;¯2s 0s;{+/0,⍵};)) Q⋄J[`bid]", "10 0 70", small},
+	{"T←⍉`sym`time`qty#(`a`b`a;2018.12.23T10.00.02 2018.12.23T10.00.03 2018.12.23T10.00.05;1 2 3;)⋄Q←⍉`sym`time`bid#(`a`b`a`a;2018.12.23T10.00.01 2018.12.23T10.00.04 2018.12.23T10.00.03 2018.12.23T10.00.05;10 20 30 40;)⋄J←T t→wj⍠(`on`window`agg#(`sym`time;¯1s 1s;{≢⍵};)) Q⋄J[`bid]", "2 1 1", small},
+	{"T←⍉`sym`time`qty#(`a`b`a;2018.12.23T10.00.02 2018.12.23T10.00.03 2018.12.23T10.00.05;1 2 3;)⋄Q←⍉`sym`time`bid#(`a`b`a`a;2018.12.23T10.00.01 2018.12.23T10.00.04 2018.12.23T10.00.03 2018.12.23T10.00.05;10 20 30 40;)⋄J←T t→wj⍠(`on`window`agg#(`sym`time;0s 0s;(`qty#({+/0,⍵};));)) Q", "fail: wj: agg: right table has no column qty", small},
+	{"T←⍉`sym`time`qty#(`a`b`a;2018.12.23T10.00.02 2018.12.23T10.00.03 2018.12.23T10.00.05;1 2 3;)⋄Q←⍉`sym`time`bid#(`a`b`a`a;2018.12.23T10.00.01 2018.12.23T10.00.04 2018.12.23T10.00.03 2018.12.23T10.00.05;10 20 30 40;)⋄T t→aj Q", "fail: aj: option on is required: the key columns followed by the time column", small},
+	{"T←⍉`sym`time`qty#(`a`b`a;2018.12.23T10.00.02 2018.12.23T10.00.03 2018.12.23T10.00.05;1 2 3;)⋄Q←⍉`sym`time`bid#(`a`b`a`a;2018.12.23T10.00.01 2018.12.23T10.00.04 2018.12.23T10.00.03 2018.12.23T10.00.05;10 20 30 40;)⋄T t→wj⍠(`on#`sym`time) Q", "fail: wj: option window must contain 2 values: lo hi", small},
+
+	{"⍝ Select, where, by", "apl/tables/select.go", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄t→select⍠(`where#({Qty>5};)) T", "Sym Qty Px\nb 8 20\na 6 30\nc 9 40", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄(`Sym`Value#(`Sym;{Qty×Px};)) t→select⍠(`where#({Qty>5};)) T", "Sym Value\nb 160\na 180\nc 360", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄(`Mx`S#({⌈/Qty};`Sym;)) t→select⍠(`where#({Qty>2};{Px<35};)) T", "Mx S\n8 a\n8 b\n8 a", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄(`Total#({+/Qty};)) t→select T", "Total\n28", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄`Qty`Sym t→select T", "Qty Sym\n3 a\n8 b\n6 a\n9 c\n2 b", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄(`N`Total#({≢⍵};{+/Qty};)) t→select⍠(`by#`Sym) T", "Sym N Total\na 2 9\nb 2 10\nc 1 9", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄t→select⍠(`by#`Sym) T", "Sym Qty Px\na 6 30\nb 2 50\nc 9 40", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄(`Total#({+/Qty};)) t→select⍠(`by#(`Odd#({2|Qty};))) T", "Odd Total\n1 12\n0 16", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄`Qty t→select⍠(`sort`desc#(`Qty;1;)) T", "Qty\n9\n8\n6\n3\n2", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄t→select⍠(`sort`limit#(`Sym`Qty;¯2;)) T", "Sym Qty Px\nb 8 20\nc 9 40", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄t→select⍠(`sort`limit#(`Sym`Qty;2;)) T", "Sym Qty Px\na 3 10\na 6 30", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄t→select⍠(`sort#(`Sym`Qty#1 ¯1)) T", "Sym Qty Px\na 6 30\na 3 10\nb 8 20\nb 2 50\nc 9 40", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄⍴t→select⍠(`where#({Qty>100};)) T", "0 3", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄`Qty`X t→select T", "fail: select: column does not exist: X", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄t→select⍠(`where#({Qty};)) T", "fail: select: where clause must return a boolean vector: apl.Int", 0},
+	{"T←⍉`Sym`Qty`Px#(`a`b`a`c`b;3 8 6 9 2;10 20 30 40 50;)⋄(`N#({⍳2};)) t→select⍠(`by#`Sym) T", "fail: select: N: result for a group must be a scalar: shape [2]", 0},
+
+	{"⍝ Box display", "apl/box.go", 0},
+	{"⎕PP←¯4⋄1 2 3", "┌3────┐\n│1 2 3│\n└int──┘", 0},
+	{"⎕PP←¯4⋄⎕PP", "¯4", 0},
+	{"⎕PP←¯4⋄⎕PP←6⋄1 2 3", "1 2 3", 0},
+	{"⍕⍠(`mode#\"box\")2 2⍴1 2 3 4", "┌2 2┐\n│1 2│\n│3 4│\n└int┘", 0},
+
 	{"⍝ Assignment, specification", "apl/operators/assign.go", 0},
 	{"X←3", "", 0},              // assign a number
 	{"-X←3", "¯3", 0},           // assign a value and use it
@@ -869,6 +1120,11 @@ var testCases = []struct {
 	{"A←1⍴1⋄S←{A[1]←2}0⋄A", "2", 0},
 	{"A←1⋄{A+←1⋄A}0⋄A", "2\n2", 0},
 	{"+X←{A←3⋄B←4}0", "4", 0},
+	{"adder←{N←⍵⋄{N+⍵}}\na3←adder 3\nN←100\na3 4", "7", 0},        // closure
+	{"adder←{N←⍵⋄{N+⍵}}\na3←adder 3\n{N←100⋄a3 ⍵}4", "7", 0},      // not dynamic
+	{"g←{A←⍵⋄h←{A×⍵}⋄h}\nk←g 5\nA←7\nk 2", "10", 0},               // returned function variable
+	{"D←{N←⍵⋄`f`g#({N+⍵};{N×⍵};)}10\nN←2\nf←D[`g]\nf 5", "50", 0}, // callbacks in a dict
+	{"N←1⋄f←{N+⍵}⋄N←2⋄f 3", "5", 0},                               // the closure is not a copy
 
 	{"⍝ Default left argument", "apl/lambda.go", 0},
 	{"f←{⍺←3⋄⍺+⍵}⋄ f 4 ⋄ 1 f 4", "7\n5", 0},
@@ -880,6 +1136,66 @@ var testCases = []struct {
 	{"⍝ Tail call", "apl/lambda.go", 0},
 	{"{⍵>1000:⍵⋄∇⍵+1}1", "1001", 0},
 
+	{"⍝ Error guards, signal", "apl/error.go", 0},
+	{"{0::¯1⋄1÷⍵}`x", "¯1", 0},
+	{"{0::⎕DMX[`code]⋄1+⍵}`x", "0", 0},
+	{"{0::⎕DMX[`expr]⋄1+⍵}`x", "(1 + ⍵)", 0},
+	{"{0::⎕DMX[`expr]⋄⍳(;)}0", "(⍳ (;))", 0}, // empty list elements
+	{"{0::⎕DMX[`message]⋄⎕eval (;)}0", "parse tree node must be a tagged list: apl.EmptyArray", 0},
+	{"{11::⎕DMX[`message]⋄'bad' ⎕signal 11}0", "bad", 0},
+	{"{12::1⋄⎕signal 11}0", "fail:", 0},
+	{"{0::⎕DMX[`code]⋄⎕signal 5}0", "5", 0},
+	{"{5 6::⎕DMX[`code]⋄⎕signal 6}0", "6", 0},
+	{"{0::`outer⋄{5::⎕signal ⎕DMX⋄⎕signal 5}⍵}0", "outer", 0}, // resignal
+	{"{0::⍵⋄⍵>3:⍵÷`x⋄∇⍵+1}0", "4", 0},                         // tail call is trapped
+	{"{A←1⋄0::A⋄A←2⋄1÷`x}0", "2", 0},
+	{"{0::⎕DMX[`stack]⋄{1+⍵}`x}0", "λ[1] ⍵:0 λ[0] ⍵:x", 0},
+	{"{0::⍴⎕DMX[`stack]⋄1+⍵}`x", "1", 0},
+	{"{0::⎕DMX[`stack]⋄⍵>2:⍵÷`x⋄∇⍵+1}0", "λ[2] ⍵:0 λ[2] ⍵:1 λ[2] ⍵:2 λ[1] ⍵:3", 0}, // trapped tail calls are normal calls
+	{"⎕signal 3", "fail: error 3", 0},
+	{"⎕signal←1", "fail: cannot assign to a system function", 0},
+	{"0::1", "fail: unexpected ::", 0},
+
+	{"⍝ Lambda operators", "apl/dop.go", 0},
+	{"+{⍺⍺/⍵}1 2 3", "6", 0},
+	{"(×⍨){⍺⍺ ⍺⍺ ⍵}3", "81", 0},
+	{"1 2 3+{⍺ ⍺⍺ ⍵}4", "5 6 7", 0},
+	{"(-{⍺⍺⍣⍵⍵⊢⍵}3)5", "¯5", 0},            // array operand
+	{"2{⍺⍺×⍵}3", "6", 0},                   // array left operand
+	{"+{{⍺⍺/⍵}¨⍵}(1 2;3 4;)", "(3;7;)", 0}, // nested lambda uses the operands
+	{"twice←{⍺⍺ ⍺⍺ ⍵}\n×⍨twice 3", "81", 0},
+	{"twice←{⍺⍺ ⍺⍺ ⍵}\n(×⍨twice)¨1 2 3", "1 16 81", 0},
+	{"f←{⍺⍺ ⍵}⋄-f 3", "¯3", 0}, // assigned in the same line
+	{"twice←{⍺⍺ ⍺⍺ ⍵}⋄×⍨twice 3", "81", 0},
+	{"h←{⍺⍺ ⍵⍵ ⍵}⋄(-h|)¯4", "¯4", 0},
+	{"{f←{⍺⍺ ⍵}⋄-f ⍵}3", "¯3", 0}, // local operator
+	{"{t←{⍺⍺ ⍺⍺ ⍵}⋄(×⍨t)¨⍵}1 2 3", "1 16 81", 0},
+	{"{f←{⍺⍺ ⍵}⋄f←-⋄f ⍵}3", "¯3", 0}, // reassigned as a function
+	{"g←{f←{⍺⍺/⍵}⋄+f ⍵}⋄g 1 2 3", "6", 0},
+	{"f←+{⍺⍺/⍵}⋄f 1 2 3", "6", 0},
+	{"fac←{⍵≤1:1⋄⍵ ⍺⍺ ∇⍵-1}\n×fac 5", "120", 0}, // ∇ is the derived function
+	{"{⍺⍺ ⍵}3", "fail:", 0},
+
+	{"⍝ Parse tree, code as data", "apl/tree.go", 0},
+	{"⎕parse '1+2'", "((function;(primitive;+;);(number;1;);(number;2;););)", 0},
+	{"⎕parse 'A+←1'", "((assignment;(identifier;A;);(number;1;);(primitive;+;););)", 0},
+	{"⎕parse '{⍵:1⋄0::2}'", "((lambda;(guard;(identifier;⍵;);(number;1;););(trap;(number;0;);(number;2;);););)", 0},
+	{"⍴⎕parse 'A←1⋄B←2'", "2", 0},
+	{"⎕eval ⎕parse 'A←⍳5⋄A[1 2]←7⋄A+←1⋄A'", "8 8 4 5 6", 0},
+	{"⎕eval ⎕parse '1⋄2'", "1\n2", 0},
+	{"⎕eval ⎕parse '{⍵≤1:1⋄⍵×∇⍵-1}5'", "120", 0},
+	{"⎕eval ⎕parse '{0::⎕DMX[`code]⋄⎕signal 5}0'", "5", 0},
+	{"⎕eval ⎕parse '(+/÷≢)1 2 3'", "2", 0},
+	{"⎕eval ⎕parse '+/[1]2 3⍴⍳6'", "5 7 9", 0},
+	{"⎕eval ⎕parse '1 2∘.×1 2'", "1 2\n2 4", 0},
+	{"⎕eval ⎕parse '(1;2 3;)'", "(1;2 3;)", 0},
+	{"twice←{⍺⍺ ⍺⍺ ⍵}\n⎕eval ⎕parse '(×⍨twice 3),2 twice 3'", "81 2 2 3", 0},
+	{"N←⊃⎕parse '-4'⋄N[2]←(\"primitive\";\"×\";)⋄⎕eval N", "1", 0},
+	{"N←⊃⎕parse '-3'⋄F←⊃⎕parse '1+2'⋄F[3]←N[3]⋄⎕eval F", "2", 0},
+	{"⎕eval (\"function\";(\"primitive\";\"+\";);(\"number\";1;);(\"number\";2;);)", "3", 0},
+	{"⎕eval (\"nonsense\";)", "fail: unknown parse tree node: nonsense", 0},
+	{"⎕eval (\"function\";(\"identifier\";\"A\";);(\"number\";1;);(\"number\";2;);)", "fail: parse tree node function: not a function: apl.numVar", 0},
+
 	{"⍝ Trains, forks, atops", "apl/train.go", 0},
 	{"-,÷ 5", "¯0.2", float},
 	{"(-,÷)5", "¯5 0.2", float},
@@ -942,6 +1258,42 @@ var testCases = []struct {
 	{"L←(1;(2;3;);4;)⋄L[2;0]←5⋄L", "(1;(2;5;);4;)", 0},
 	{"L←(1;(2;3;);4;)⋄L[2;¯1]×←5⋄L", "(1;(10;3;);4;)", 0},
 
+	{"⍝ Nested arrays, enabled with a→nested 1", "apl/nested.go", 0},
+	{"(1 2)(3 4)", "fail: cannot reduce expression", 0},
+	{`(1 2)(3 4 5)`, "┌───┬─────┐\n│1 2│3 4 5│\n└───┴─────┘", nested},
+	{`1 2 (3 4) 5`, "┌─┬─┬───┬─┐\n│1│2│3 4│5│\n└─┴─┴───┴─┘", nested},
+	{`⊂1 2 3`, "┌─────┐\n│1 2 3│\n└─────┘", nested},
+	{`⍴(1 2)(3 4 5)`, "2", nested},
+	{`⍴⍴⊂1 2`, "0", nested},
+	{`≢⊂1 2`, "1", nested},
+	{`≡⊂1 2 3`, "2", nested},
+	{`≡(1 2)(3 4 5)`, "2", nested},
+	{`≡1 (2 (3 4))`, "3", nested},
+	{`≡1 2`, "1", nested},
+	{`⊃(1 2)(3 4 5)`, "1 2", nested},
+	{`↑(1 2)(3 4 5)`, "1 2 0\n3 4 5", nested},
+	{`↑(1 2)3`, "1 2\n3 0", nested},
+	{`2 2⍴(1 2)(3 4 5)(6)(7 8)`, "┌───┬─────┐\n│1 2│3 4 5│\n├───┼─────┤\n│6 │7 8 │\n└───┴─────┘", nested},
+	{`1 1 2 2 0 3⊆⍳6`, "┌───┬───┬─┐\n│1 2│3 4│6│\n└───┴───┴─┘", nested},
+	{`⊆1 2 3`, "┌─────┐\n│1 2 3│\n└─────┘", nested},
+	{`{⍳⍵}¨1 2 3`, "┌─┬───┬─────┐\n│1│1 2│1 2 3│\n└─┴───┴─────┘", nested},
+	{`⍴¨(1 2)(3 4 5)`, "┌─┬─┐\n│2│3│\n└─┴─┘", nested},
+	{`1 2 3,¨⊂4 5`, "┌─────┬─────┬─────┐\n│1 4 5│2 4 5│3 4 5│\n└─────┴─────┴─────┘", nested},
+	{`(1 2)(3 4)≡(1 2)(3 4)`, "1", nested},
+	{`(1 2)(3 4)≡(1 2)(3 5)`, "0", nested},
+	{`((1 2)(3 4)),⊂5 6`, "┌───┬───┬───┐\n│1 2│3 4│5 6│\n└───┴───┴───┘", nested},
+	{`3⍴⊂1 2`, "┌───┬───┬───┐\n│1 2│1 2│1 2│\n└───┴───┴───┘", nested},
+	{`(2 2⍴⍳4)(5 6)`, "┌───┬───┐\n│1 2│5 6│\n│3 4│ │\n└───┴───┘", nested},
+	{`X←(1 2)(3 4) ⋄ X[2]`, "3 4", nested},
+	{`{⍵×2}⍣2 (1 2)`, "4 8", nested},
+	{`X←(1 2)(3 4)⋄X=X`, "┌───┬───┐\n│1 1│1 1│\n└───┴───┘", nested},
+	{`X←(1 2)(3 4)⋄X+1`, "┌───┬───┐\n│2 3│4 5│\n└───┴───┘", nested},
+	{`(1 2)(3 4)+10 20`, "┌─────┬─────┐\n│11 12│23 24│\n└─────┴─────┘", nested},
+	{`-(1 2)(3 4)`, "┌─────┬─────┐\n│¯1 ¯2│¯3 ¯4│\n└─────┴─────┘", nested},
+	{`X←(1 2)(3 4)⋄2⊃X`, "3 4", nested},
+	{`X←(1 2)(3 4)⋄2 1⊃X`, "3", nested},
+	{`X←(1 2)(3 4)⋄3⊃X`, "fail: pick: index out of range", nested},
+
 	{"⍝ Dictionaries", "apl/object.go", 0},
 	{"D←`alpha#1 2 3⋄D[`alpha]←`xyz⋄D", "alpha: xyz", 0},
 	{"D←`alpha#1⋄D[`alpha`beta]←3 4⋄D", "alpha: 3\nbeta: 4", 0},
@@ -1099,8 +1451,9 @@ func testCompare(got, exp string) bool {
 }
 
 const (
-	float int = 1 << iota // only for floating point towers
-	small                 // normal tower only
+	float  int = 1 << iota // only for floating point towers
+	small                  // normal tower only
+	nested                 // nested arrays enabled
 )
 
 func TestNormal(t *testing.T) {
@@ -1184,6 +1537,10 @@ func testApl(t *testing.T, tower func(*apl.Apl), skip int) {
 		operators.Register(a)
 		aplstrings.Register(a, "s")
 		xgo.Register(a, "go")
+		tables.Register(a, "t")
+		if tc.flag&nested != 0 {
+			a.Nested = true
+		}
 
 		mustfail := strings.HasPrefix(tc.exp, "fail:")
 		lines := strings.Split(tc.in, "\n")
@@ -109,7 +109,8 @@ func (ars arraysWithAxis) To(a *apl.Apl, L, R apl.Value) (apl.Value, apl.Value,
 func (ars arraysWithAxis) String(f apl.Format) string { return "arithmetic arrays with axis" }
 
 // array1 tries to apply the elementary function returned by arith1(fn)
-// monadically to each element of the array R
+// monadically to each element of the array R.
+// Items of nested arrays are pervaded by calling the primitive recursively.
 func array1(symbol string, fn func(*apl.Apl, apl.Value) (apl.Value, bool)) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
 	efn := arith1(symbol, fn)
 	return func(a *apl.Apl, _ apl.Value, R apl.Value) (apl.Value, error) {
@@ -118,7 +119,13 @@ func array1(symbol string, fn func(*apl.Apl, apl.Value) (apl.Value, bool)) func(
 		same := true
 		var t reflect.Type
 		for i := range res.Values {
-			val, err := efn(a, nil, ar.At(i))
+			var val apl.Value
+			var err error
+			if v := ar.At(i); isItemArray(v) {
+				val, err = apl.Primitive(symbol).Call(a, nil, v)
+			} else {
+				val, err = efn(a, nil, v)
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -142,6 +149,7 @@ func array1(symbol string, fn func(*apl.Apl, apl.Value) (apl.Value, bool)) func(
 // array2 tries to apply the elementary function returned by arith2(fn)
 // dyadically to the elements of the arrays L and R.
 // L and R have been tested and converted by arrays.
+// Items of nested arrays are pervaded by calling the primitive recursively.
 func array2(symbol string, fn func(*apl.Apl, apl.Value, apl.Value) (apl.Value, bool)) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
 	efn := arith2(symbol, fn)
 	return func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
@@ -175,7 +183,13 @@ func array2(symbol string, fn func(*apl.Apl, apl.Value, apl.Value) (apl.Value, b
 			if isRarray {
 				rv = ar.At(i)
 			}
-			val, err := efn(a, lv, rv)
+			var val apl.Value
+			var err error
+			if isItemArray(lv) || isItemArray(rv) {
+				val, err = apl.Primitive(symbol).Call(a, lv, rv)
+			} else {
+				val, err = efn(a, lv, rv)
+			}
 			if err != nil {
 				return nil, err
 			} else {
@@ -196,6 +210,13 @@ func array2(symbol string, fn func(*apl.Apl, apl.Value, apl.Value) (apl.Value, b
 	}
 }
 
+// isItemArray returns true, if the item of an array is an array itself,
+// which is the case for nested arrays.
+func isItemArray(v apl.Value) bool {
+	_, ok := v.(apl.Array)
+	return ok
+}
+
 // ArrayAxis is like array2 but with R bound in an axis specification.
 func arrayAxis(symbol string, fn func(*apl.Apl, apl.Value, apl.Value) (apl.Value, bool)) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
 	efn := arith2(symbol, fn)
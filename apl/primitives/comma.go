@@ -212,6 +212,17 @@ func catenate(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	al, isLarray := L.(apl.Array)
 	ar, isRarray := R.(apl.Array)
 
+	// An enclosed scalar is extended like a simple scalar.
+	if apl.IsEnclosed(L) {
+		L, isLarray = apl.Disclose(L), false
+	}
+	if apl.IsEnclosed(R) {
+		R, isRarray = apl.Disclose(R), false
+		if x < 0 && isLarray {
+			x = len(al.Shape()) - 1
+		}
+	}
+
 	// Left or right is an empty array
 	if isLarray && al.Size() == 0 {
 		return R, nil
@@ -287,6 +298,9 @@ func catenate(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 			return nil, fmt.Errorf("catenate: all axis lengths except for the catenation axis must match")
 		}
 	}
+	if err := a.CheckSize(apl.Prod(newshape)); err != nil {
+		return nil, err
+	}
 	res := apl.NewMixed(newshape)
 
 	// Iterate over combined elements, taking from L or R.
@@ -59,29 +59,29 @@ func compare(symbol string) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, boo
 	return func(a *apl.Apl, L apl.Value, R apl.Value) (apl.Value, bool) {
 		switch symbol {
 		case "=":
-			return equals(L, R)
+			return equals(a, L, R)
 		case "<":
-			return less(L, R)
+			return less(a, L, R)
 		case ">":
-			eq, ls, ok := equalless(L, R)
+			eq, ls, ok := equalless(a, L, R)
 			if ok == false {
 				return nil, false
 			}
 			return apl.Bool(!eq && !ls), true
 		case "≠":
-			eq, ok := equals(L, R)
+			eq, ok := equals(a, L, R)
 			if ok == false {
 				return nil, false
 			}
 			return apl.Bool(!eq), true
 		case "≤":
-			eq, ls, ok := equalless(L, R)
+			eq, ls, ok := equalless(a, L, R)
 			if ok == false {
 				return nil, false
 			}
 			return apl.Bool(eq || ls), true
 		case "≥":
-			eq, ls, ok := equalless(L, R)
+			eq, ls, ok := equalless(a, L, R)
 			if ok == false {
 				return nil, false
 			}
@@ -91,21 +91,42 @@ func compare(symbol string) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, boo
 	}
 }
 
-func equalless(L, R apl.Value) (apl.Bool, apl.Bool, bool) {
-	eq, ok := equals(L, R)
+func equalless(a *apl.Apl, L, R apl.Value) (apl.Bool, apl.Bool, bool) {
+	eq, ok := equals(a, L, R)
 	if ok == false {
 		return false, false, false
 	}
-	ls, ok := less(L, R)
+	ls, ok := less(a, L, R)
 	if ok == false {
 		return false, false, false
 	}
 	return eq, ls, true
 }
-func equals(L, R apl.Value) (apl.Bool, bool) {
+
+// equals compares L and R, which have the same type.
+// Numbers that implement tolerantEqualer compare with the comparison tolerance ⎕CT or ⎕DCT.
+// Values of types that are not comparable, such as arrays, are not supported.
+func equals(a *apl.Apl, L, R apl.Value) (apl.Bool, bool) {
+	switch l := L.(type) {
+	case apl.Int:
+		r, ok := R.(apl.Int)
+		return apl.Bool(ok && l == r), true
+	case apl.Bool:
+		r, ok := R.(apl.Bool)
+		return apl.Bool(ok && l == r), true
+	case apl.String:
+		r, ok := R.(apl.String)
+		return apl.Bool(ok && l == r), true
+	}
+	if eq, ok := L.(tolerantEqualer); ok {
+		return eq.TolerantEquals(R, a.Tolerance)
+	}
 	if eq, ok := L.(equaler); ok {
 		return eq.Equals(R)
 	}
+	if t := reflect.TypeOf(L); t == nil || t.Comparable() == false {
+		return false, false
+	}
 	return apl.Bool(L == R), true
 }
 
@@ -113,9 +134,21 @@ type equaler interface {
 	Equals(apl.Value) (apl.Bool, bool)
 }
 
-func less(L, R apl.Value) (apl.Bool, bool) {
+type tolerantEqualer interface {
+	TolerantEquals(apl.Value, apl.Tolerance) (apl.Bool, bool)
+}
+
+// less compares L and R, which have the same type.
+// L is not less than R, if both are tolerantly equal.
+func less(a *apl.Apl, L, R apl.Value) (apl.Bool, bool) {
 	if ls, ok := L.(lesser); ok {
-		return ls.Less(R)
+		b, ok := ls.Less(R)
+		if b == true && ok {
+			if eq, _ := equals(a, L, R); eq {
+				return false, true
+			}
+		}
+		return b, ok
 	}
 	return false, false
 }
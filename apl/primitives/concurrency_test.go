@@ -0,0 +1,38 @@
+package primitives
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ktye/iv/apl"
+	"github.com/ktye/iv/apl/numbers"
+	"github.com/ktye/iv/apl/operators"
+	"github.com/ktye/iv/apl/xgo"
+)
+
+// TestConcurrentChannels runs pipelines with lambda functions in parallel.
+// Run it with go test -race.
+func TestConcurrentChannels(t *testing.T) {
+	testCases := []struct {
+		in, exp string
+	}{
+		{"N←1⋄C←{⍵+N}¨go→source 100⋄D←{A←⍵⋄A×2}¨go→source 100⋄(+/C)+(+/D)", "14950"},
+		{"f←{⍺+⍵}⋄C←{f/⍳⍵}¨1+go→source 50⋄D←{+/⍳⍵}¨1+go→source 50⋄(+/C)-(+/D)", "0"},
+		{"C←{⍵>1:(∇⍵-1)+∇⍵-2⋄⍵}¨go→source 12⋄D←{⍵>1:(∇⍵-1)+∇⍵-2⋄⍵}¨go→source 12⋄(+/C)-(+/D)", "0"},
+	}
+	for _, tc := range testCases {
+		var buf bytes.Buffer
+		a := apl.New(&buf)
+		numbers.Register(a)
+		Register(a)
+		operators.Register(a)
+		xgo.Register(a, "go")
+
+		if err := a.ParseAndEval(tc.in); err != nil {
+			t.Fatalf("%s: %s", tc.in, err)
+		} else if got := strings.TrimSpace(buf.String()); got != tc.exp {
+			t.Fatalf("%s: expected %s, got %s", tc.in, tc.exp, got)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package primitives
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ktye/iv/apl"
+	"github.com/ktye/iv/apl/numbers"
+	"github.com/ktye/iv/apl/operators"
+)
+
+// TestDebug should be in apl where the Debugger is defined.
+// But there are no primitives available.
+func TestDebug(t *testing.T) {
+	newApl := func(w *bytes.Buffer) *apl.Apl {
+		a := apl.New(w)
+		numbers.Register(a)
+		Register(a)
+		operators.Register(a)
+		if err := a.ParseAndEval("f←{⍵=0:0⋄g ⍵-1}⋄g←{f ⍵}"); err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+
+	// Trace.
+	var out, trace bytes.Buffer
+	a := newApl(&out)
+	a.Debugger = &apl.Debugger{Trace: &trace}
+	if err := a.ParseAndEval("f 1"); err != nil {
+		t.Fatal(err)
+	}
+	exp := `f ⍵:1
+  [0] (⍵ = 0): 0
+  [1] (g (⍵ - 1))
+  g ⍵:0
+    [0] (f ⍵)
+    f ⍵:0
+      [0] (⍵ = 0): 1
+    f → 0
+  g → 0
+f → 0
+`
+	if got := trace.String(); got != exp {
+		t.Fatalf("trace: expected:\n%s\ngot:\n%s", exp, got)
+	}
+
+	// Breakpoint without a handler.
+	a = newApl(&out)
+	a.Debugger = &apl.Debugger{Breakpoints: map[string]bool{"g": true}}
+	err := a.ParseAndEval("f 2")
+	if err == nil || err.Error() != "breakpoint: g" {
+		t.Fatalf("expected breakpoint error, got %v", err)
+	}
+	e, ok := err.(apl.Error)
+	if ok == false {
+		t.Fatalf("expected apl.Error: %T", err)
+	}
+	if got, exp := apl.StackTrace(e.Stack, a.Format), "g[0] ⍵:1\nf[1] ⍵:2\n"; got != exp {
+		t.Fatalf("stack: expected:\n%s\ngot:\n%s", exp, got)
+	}
+	if s := a.Stack(); len(s) != 0 {
+		t.Fatalf("stack is not empty after error: %v", s)
+	}
+
+	// Breakpoint handler evaluates in the environment of the lambda function.
+	a = newApl(&out)
+	var args []string
+	a.Debugger = &apl.Debugger{
+		Breakpoints: map[string]bool{"g": true},
+		Break: func(a *apl.Apl, stack []apl.Frame) error {
+			args = append(args, a.Lookup("⍵").String(a.Format))
+			if len(stack) > 4 {
+				return fmt.Errorf("stop")
+			}
+			return nil
+		},
+	}
+	out.Reset()
+	if err := a.ParseAndEval("f 2"); err != nil {
+		t.Fatal(err)
+	} else if got := strings.Join(args, " "); got != "1 0" {
+		t.Fatalf("breakpoint arguments: expected 1 0, got %s", got)
+	}
+	if err := a.ParseAndEval("f 5"); err == nil || strings.HasPrefix(err.Error(), "stop") == false {
+		t.Fatalf("expected stop error, got %v", err)
+	}
+}
@@ -14,12 +14,14 @@ func init() {
 		doc:    "decode, polynom, base value",
 		Domain: Dyadic(Split(ToArray(nil), ToArray(nil))),
 		fn:     decode,
+		inv:    decodeInverse,
 	})
 	register(primitive{
 		symbol: "⊤",
 		doc:    "encode, representation",
 		Domain: Dyadic(nil),
 		fn:     encode,
+		inv:    inverse("⊥", false),
 	})
 }
 
@@ -16,18 +16,19 @@ func init() {
 		doc, doc2 string
 		monadic   func(*apl.Apl, apl.Value) (apl.Value, bool)
 		dyadic    func(*apl.Apl, apl.Value, apl.Value) (apl.Value, bool)
+		inv, inv2 func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error)
 	}{
-		{"+", "identity, complex conjugate", "plus, addition", add, add2},
-		{"-", "reverse sign", "substract, substraction", sub, sub2},
-		{"×", "signum, sign of, direction", "multiply", mul, mul2},
-		{"÷", "reciprocal", "div, division, divide", div, div2},
-		{"*", "exponential", "power", pow, pow2},
-		{"⍟", "natural logarithm", "log, logarithm", log, log2},
-		{"|", "magnitude, absolute value", "residue, modulo", abs, abs2},
-		{"⌊", "floor", "min, minimum", min, min2},
-		{"⌈", "ceil", "max, maximum", max, max2},
-		{"!", "factorial", "binomial", factorial, binomial},
-		{"○", "pi times", "circular, trigonometric", pitimes, circular},
+		{"+", "identity, complex conjugate", "plus, addition", add, add2, inverse("+", false), inverse("-", true)},
+		{"-", "reverse sign", "substract, substraction", sub, sub2, inverse("-", false), inverse("-", false)},
+		{"×", "signum, sign of, direction", "multiply", mul, mul2, nil, inverse("÷", true)},
+		{"÷", "reciprocal", "div, division, divide", div, div2, inverse("÷", false), inverse("÷", false)},
+		{"*", "exponential", "power", pow, pow2, inverse("⍟", false), inverse("⍟", false)},
+		{"⍟", "natural logarithm", "log, logarithm", log, log2, inverse("*", false), inverse("*", false)},
+		{"|", "magnitude, absolute value", "residue, modulo", abs, abs2, nil, nil},
+		{"⌊", "floor", "min, minimum", min, min2, nil, nil},
+		{"⌈", "ceil", "max, maximum", max, max2, nil, nil},
+		{"!", "factorial", "binomial", factorial, binomial, nil, nil},
+		{"○", "pi times", "circular, trigonometric", pitimes, circular, pitimesInverse, circularInverse},
 	}
 
 	for _, e := range tab {
@@ -36,36 +37,42 @@ func init() {
 			doc:    e.doc,
 			Domain: Monadic(IsScalar(nil)),
 			fn:     arith1(e.symbol, e.monadic),
+			inv:    e.inv,
 		})
 		register(primitive{
 			symbol: e.symbol,
 			doc:    e.doc,
 			Domain: Monadic(IsArray(nil)),
 			fn:     array1(e.symbol, e.monadic),
+			inv:    e.inv,
 		})
 		register(primitive{
 			symbol: e.symbol,
 			doc:    e.doc,
 			Domain: Monadic(Or(IsObject(nil), IsTable(nil))),
 			fn:     table1(e.symbol, e.monadic),
+			inv:    e.inv,
 		})
 		register(primitive{
 			symbol: e.symbol,
 			doc:    e.doc,
 			Domain: Monadic(IsChannel(nil)),
 			fn:     channel1(e.symbol, e.monadic),
+			inv:    e.inv,
 		})
 		register(primitive{
 			symbol: e.symbol,
 			doc:    e.doc2,
 			Domain: Dyadic(Split(IsScalar(nil), IsScalar(nil))),
 			fn:     arith2(e.symbol, e.dyadic),
+			inv:    e.inv2,
 		})
 		register(primitive{
 			symbol: e.symbol,
 			doc:    e.doc2,
 			Domain: arrays{},
 			fn:     array2(e.symbol, e.dyadic),
+			inv:    e.inv2,
 		})
 		register(primitive{
 			symbol: e.symbol,
@@ -78,18 +85,21 @@ func init() {
 			doc:    e.doc2,
 			Domain: Dyadic(Any(Or(IsTable(nil), IsObject(nil)))),
 			fn:     tableAny(e.symbol, e.dyadic),
+			inv:    e.inv2,
 		})
 		register(primitive{
 			symbol: e.symbol,
 			doc:    e.doc2,
 			Domain: Dyadic(Both(Or(IsTable(nil), IsObject(nil)))),
 			fn:     tableBoth(e.symbol, e.dyadic),
+			inv:    e.inv2,
 		})
 		register(primitive{
 			symbol: e.symbol,
 			doc:    e.doc2,
 			Domain: Dyadic(Split(nil, IsChannel(nil))),
 			fn:     channel2(e.symbol, e.dyadic),
+			inv:    e.inv2,
 		})
 	}
 	register(primitive{
@@ -334,7 +344,7 @@ func abs(a *apl.Apl, R apl.Value) (apl.Value, bool) {
 	if err != nil {
 		return nil, false
 	}
-	if isless, ok := less(r, zero); ok == false {
+	if isless, ok := less(a, r, zero); ok == false {
 		return nil, false
 	} else if isless {
 		return sub(a, R)
@@ -378,16 +388,23 @@ func abs2(a *apl.Apl, L, R apl.Value) (apl.Value, bool) {
 type floorer interface {
 	Floor() (apl.Value, bool)
 }
+type tolerantFloorer interface {
+	TolerantFloor(apl.Tolerance) (apl.Value, bool)
+}
 
 // min returns the largest integer that is less or equal to R
+// Floats that are tolerantly equal to the next integer return that integer.
 func min(a *apl.Apl, R apl.Value) (apl.Value, bool) {
+	if floor, ok := R.(tolerantFloorer); ok {
+		return floor.TolerantFloor(a.Tolerance)
+	}
 	if floor, ok := R.(floorer); ok {
 		return floor.Floor()
 	}
 	return nil, false
 }
 func min2(a *apl.Apl, L, R apl.Value) (apl.Value, bool) {
-	if isless, ok := less(L, R); ok == false {
+	if isless, ok := less(a, L, R); ok == false {
 		return nil, false
 	} else {
 		if isless {
@@ -402,16 +419,23 @@ func min2(a *apl.Apl, L, R apl.Value) (apl.Value, bool) {
 type ceiler interface {
 	Ceil() (apl.Value, bool)
 }
+type tolerantCeiler interface {
+	TolerantCeil(apl.Tolerance) (apl.Value, bool)
+}
 
 // max returns the smallest integer that is larger or equal to R
+// Floats that are tolerantly equal to the previous integer return that integer.
 func max(a *apl.Apl, R apl.Value) (apl.Value, bool) {
+	if ceil, ok := R.(tolerantCeiler); ok {
+		return ceil.TolerantCeil(a.Tolerance)
+	}
 	if ceil, ok := R.(ceiler); ok {
 		return ceil.Ceil()
 	}
 	return nil, false
 }
 func max2(a *apl.Apl, L, R apl.Value) (apl.Value, bool) {
-	if isless, ok := less(L, R); ok == false {
+	if isless, ok := less(a, L, R); ok == false {
 		return nil, false
 	} else {
 		if isless {
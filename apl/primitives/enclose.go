@@ -1,6 +1,7 @@
 package primitives
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/ktye/iv/apl"
@@ -32,6 +33,24 @@ func init() {
 		Domain: Monadic(IsList(nil)),
 		fn:     first,
 	})
+	register(primitive{
+		symbol: "⊂",
+		doc:    "enclose",
+		Domain: Monadic(Nested(nil)),
+		fn:     enclose,
+	})
+	register(primitive{
+		symbol: "⊃",
+		doc:    "disclose, first",
+		Domain: Monadic(Nested(IsArray(nil))),
+		fn:     disclose,
+	})
+	register(primitive{
+		symbol: "⊃",
+		doc:    "pick",
+		Domain: Dyadic(Split(ToIndexArray(nil), Nested(IsArray(nil)))),
+		fn:     pick,
+	})
 	register(primitive{
 		symbol: "⊃",
 		doc:    "split string",
@@ -104,3 +123,48 @@ func first(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 	}
 	return r[0], nil
 }
+
+// enclose returns an enclosed scalar, if nested arrays are enabled.
+// A simple scalar is returned unchanged.
+func enclose(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	return apl.Enclose(R), nil
+}
+
+// disclose returns the item of an enclosed scalar or the first item of an array,
+// if nested arrays are enabled.
+//	⊃(1 2)(3 4)
+func disclose(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	ar := R.(apl.Array)
+	if ar.Size() == 0 {
+		return apl.EmptyArray{}, nil
+	}
+	return ar.At(0), nil
+}
+
+// pick selects an item from a nested vector, if nested arrays are enabled.
+// Each element of L indexes one level deeper.
+//	2⊃(1 2)(3 4)
+//	2 1⊃(1 2)(3 4)
+func pick(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	idx := L.(apl.IntArray)
+	if len(idx.Dims) > 1 {
+		return nil, fmt.Errorf("pick: left argument must be a vector")
+	}
+	v := R
+	for _, n := range idx.Ints {
+		v = apl.Disclose(v)
+		ar, ok := v.(apl.Array)
+		if ok == false {
+			return nil, fmt.Errorf("pick: depth error")
+		}
+		if d := ar.Shape(); len(d) != 1 {
+			return nil, fmt.Errorf("pick: rank error: item has rank %d", len(d))
+		}
+		i := n - a.Origin
+		if i < 0 || i >= ar.Size() {
+			return nil, fmt.Errorf("pick: index out of range")
+		}
+		v = ar.At(i)
+	}
+	return v, nil
+}
@@ -15,9 +15,7 @@ func init() {
 		symbol: "⍕",
 		doc:    "format, convert to string",
 		Domain: Monadic(nil),
-		fn: func(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
-			return apl.String(R.String(a.Format)), nil
-		},
+		fn:     format,
 	})
 	register(primitive{
 		symbol: "⍕",
@@ -49,15 +47,34 @@ func init() {
 // Format converts the argument to string.
 // If L is a number it is used as the precision (sets PP).
 // If L is a string L is used as a format string.
-// Special formatting is used, if the string is "csv", "json", "mat" or "x".
+// Special formatting is used, if the string is "csv", "json", "mat", "x" or "box".
+//
+// Monadic format uses the variant options PP for the precision and mode for the format string:
+//	⍕⍠(`mode#`json) R
+// The option PW folds wide arrays at the page width. Format does not elide arrays.
+// Columns of matrices and tables are formatted with pictures by the system function ⎕fmt.
 func format(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	f := apl.Format{
 		PP:  a.Format.PP,
 		Fmt: make(map[reflect.Type]string),
+		Box: a.Format.Box,
 	}
 	for k, v := range a.Format.Fmt {
 		f.Fmt[k] = v
 	}
+	if n, ok := a.Option("PP").(apl.Number); ok {
+		if i, ok := n.ToIndex(); ok {
+			f.PP = i
+		}
+	}
+	if n, ok := a.Option("PW").(apl.Number); ok {
+		if i, ok := n.ToIndex(); ok && i >= 0 {
+			f.PW = i
+		}
+	}
+	if s, ok := a.Option("mode").(apl.String); ok && L == nil {
+		L = s
+	}
 
 	if n, ok := L.(apl.Number); ok {
 		if i, ok := n.ToIndex(); ok {
@@ -73,6 +90,8 @@ func format(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 			f.PP = -3
 		case "x":
 			f.PP = -16
+		case "box":
+			f.Box = true
 		default:
 			t := reflect.TypeOf(R)
 			f.Fmt[t] = string(s)
@@ -90,8 +109,10 @@ func formatTable(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	if d.At(apl.String("CSV")) != nil {
 		return formatCsv(a.Format, d, R)
 	}
+	f := a.Format
+	f.PW, f.PH = 0, 0
 	var b bytes.Buffer
-	if err := t.WriteFormatted(a.Format, d, &b); err != nil {
+	if err := t.WriteFormatted(f, d, &b); err != nil {
 		return nil, err
 	}
 	return apl.String(b.Bytes()), nil
@@ -160,6 +181,7 @@ func execute(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 // ParseData parses data from strings that has been written with ¯1⍕V.
 // L may be "A", "D" or "T" for array, dict or table.
 // If L is a value of type array, dict or table it is used as a prototype with stricter requirements.
+// Tables may also be read from csv or tsv, see apl.ParseTable.
 func parseData(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	var p apl.Value
 	ls, ok := L.(apl.String)
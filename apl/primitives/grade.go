@@ -2,11 +2,13 @@ package primitives
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"sort"
 
 	"github.com/ktye/iv/apl"
 	. "github.com/ktye/iv/apl/domain"
+	"github.com/ktye/iv/apl/numbers"
 )
 
 func init() {
@@ -34,26 +36,61 @@ func init() {
 		Domain: Dyadic(Split(IsVector(nil), IsArray(nil))),
 		fn:     grade2(false),
 	})
+	register(primitive{
+		symbol: "⍋",
+		doc:    "grade up table rows",
+		Domain: Monadic(Or(IsTable(nil), IsObject(nil))),
+		fn:     gradeTable(true),
+	})
+	register(primitive{
+		symbol: "⍒",
+		doc:    "grade down table rows",
+		Domain: Monadic(Or(IsTable(nil), IsObject(nil))),
+		fn:     gradeTable(false),
+	})
+	register(primitive{
+		symbol: "⍋",
+		doc:    "grade up table rows by columns",
+		Domain: Dyadic(Split(nil, Or(IsTable(nil), IsObject(nil)))),
+		fn:     gradeTable(true),
+	})
+	register(primitive{
+		symbol: "⍒",
+		doc:    "grade down table rows by columns",
+		Domain: Dyadic(Split(nil, Or(IsTable(nil), IsObject(nil)))),
+		fn:     gradeTable(false),
+	})
 }
 
+// grade is the monadic grade up/down.
+// The variant option collation sets a collating sequence, which is used as in the dyadic form:
+//	⍋⍠(`collation#'cab')'abcab'
 func grade(up bool) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
 	return func(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
-		si, err := gradeSetup(a, R)
-		if err != nil {
-			return nil, err
+		if c := a.Option("collation"); c != nil {
+			return grade2(up)(a, c, R)
 		}
-		if up {
-			sort.Sort(si)
-		} else {
-			sort.Sort(sort.Reverse(si))
-		}
-		return apl.IntArray{
-			Ints: si.idx,
-			Dims: []int{len(si.idx)},
-		}, nil
+		return gradeIndexes(a, R, up)
 	}
 }
 
+// gradeIndexes returns the sort indexes of R.
+func gradeIndexes(a *apl.Apl, R apl.Value, up bool) (apl.Value, error) {
+	si, err := gradeSetup(a, R)
+	if err != nil {
+		return nil, err
+	}
+	if up {
+		sort.Stable(si)
+	} else {
+		sort.Stable(sort.Reverse(si))
+	}
+	return apl.IntArray{
+		Ints: si.idx,
+		Dims: []int{len(si.idx)},
+	}, nil
+}
+
 // gradeSetup prepares grading.
 func gradeSetup(a *apl.Apl, R apl.Value) (sortIndexes, error) {
 	ar := R.(apl.Array)
@@ -177,14 +214,134 @@ func grade2(up bool) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
 		if err != nil {
 			return nil, err
 		}
-		g := grade(up)
-		return g(a, nil, LiotaR)
+		return gradeIndexes(a, LiotaR, up)
 	}
 }
 
+// gradeTable grades the rows of a table, or of a dict with vector values of the same length.
+// The grade is stable: equal rows keep their order.
+//
+// Without L, rows are compared by all columns.
+// L selects the columns, it is a column name, a vector of names or a dict of names to directions.
+// A negative direction sorts the column in descending order:
+//	`Sym`Time ⍋T
+//	(`Sym`Time#1 ¯1)⍋T
+// Grade down reverses all directions.
+// NaN is less than all other numbers, it comes first in ascending and last in descending order.
+//
+// Indexing a table with the result sorts the rows: T[⍋T].
+// A dict can be sorted by converting it to a table: (⍉D)[⍋D].
+func gradeTable(up bool) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
+	return func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		cols, rows, err := tableColumns(R.(apl.Object))
+		if err != nil {
+			return nil, err
+		}
+		keys, desc, err := gradeKeys(L, R.(apl.Object))
+		if err != nil {
+			return nil, err
+		}
+
+		si := sortIndexes{
+			b:    make([][]apl.Value, rows),
+			idx:  make([]int, rows),
+			desc: desc,
+		}
+		for i := range si.idx {
+			si.idx[i] = i + a.Origin
+		}
+		if rows == 0 {
+			return apl.IntArray{Dims: []int{0}}, nil
+		}
+		for _, k := range keys {
+			s, err := gradeSetup(a, cols[k])
+			if err != nil {
+				return nil, fmt.Errorf("grade column %s: %s", k.String(a.Format), err)
+			}
+			for i := range si.b {
+				si.b[i] = append(si.b[i], s.b[i][0])
+			}
+		}
+		if up == false {
+			for i := range si.desc {
+				si.desc[i] = !si.desc[i]
+			}
+		}
+		sort.Stable(si)
+		return apl.IntArray{
+			Ints: si.idx,
+			Dims: []int{len(si.idx)},
+		}, nil
+	}
+}
+
+// tableColumns returns the columns of a table or a dict and the number of rows.
+// All values of a dict must be vectors of the same length.
+func tableColumns(o apl.Object) (map[apl.Value]apl.Array, int, error) {
+	cols := make(map[apl.Value]apl.Array)
+	rows := -1
+	if t, ok := o.(apl.Table); ok {
+		rows = t.Rows
+	}
+	for _, k := range o.Keys() {
+		ar, ok := o.At(k).(apl.Array)
+		if ok == false || len(ar.Shape()) != 1 || (rows >= 0 && ar.Size() != rows) {
+			return nil, 0, fmt.Errorf("grade: dict values must be vectors of the same length")
+		}
+		rows = ar.Size()
+		cols[k] = ar
+	}
+	if rows < 0 {
+		rows = 0
+	}
+	return cols, rows, nil
+}
+
+// gradeKeys returns the column names to grade by and their directions.
+func gradeKeys(L apl.Value, o apl.Object) ([]apl.Value, []bool, error) {
+	var keys []apl.Value
+	var desc []bool
+	switch v := L.(type) {
+	case nil:
+		keys = o.Keys()
+	case apl.Object:
+		for _, k := range v.Keys() {
+			n, ok := v.At(k).(apl.Number)
+			if ok == false {
+				return nil, nil, fmt.Errorf("grade: direction of column %s must be a number: %T", k.String(apl.Format{}), v.At(k))
+			}
+			d, ok := n.ToIndex()
+			if ok == false || d == 0 {
+				return nil, nil, fmt.Errorf("grade: direction of column %s must be 1 or ¯1", k.String(apl.Format{}))
+			}
+			keys = append(keys, k)
+			desc = append(desc, d < 0)
+		}
+	case apl.Array:
+		for i := 0; i < v.Size(); i++ {
+			keys = append(keys, v.At(i))
+		}
+	default:
+		keys = []apl.Value{L}
+	}
+	if desc == nil {
+		desc = make([]bool, len(keys))
+	}
+	for _, k := range keys {
+		if o.At(k) == nil {
+			return nil, nil, fmt.Errorf("grade: column does not exist: %s", k.String(apl.Format{}))
+		}
+	}
+	return keys, desc, nil
+}
+
+// sortIndexes sorts the index vector idx by the values in b.
+// Each element of b contains the values of a row that are compared in order.
+// If desc is not nil, it reverses the order for the corresponding values.
 type sortIndexes struct {
-	b   [][]apl.Value
-	idx []int
+	b    [][]apl.Value
+	idx  []int
+	desc []bool
 }
 
 func (s sortIndexes) Len() int { return len(s.b) }
@@ -192,6 +349,19 @@ func (s sortIndexes) Less(i, j int) bool {
 	x := s.b[i]
 	y := s.b[j]
 	for n := range x {
+		if n < len(s.desc) && s.desc[n] {
+			x, y = s.b[j], s.b[i]
+		} else {
+			x, y = s.b[i], s.b[j]
+		}
+		// NaN is less than any number.
+		if xn, yn := isNaN(x[n]), isNaN(y[n]); xn && yn {
+			continue
+		} else if xn {
+			return true
+		} else if yn {
+			return false
+		}
 		xl := x[n].(lesser)
 		yl := y[n].(lesser)
 		if isless, _ := xl.Less(y[n]); isless {
@@ -207,3 +377,8 @@ func (s sortIndexes) Swap(i, j int) {
 	s.b[i], s.b[j] = s.b[j], s.b[i]
 	s.idx[i], s.idx[j] = s.idx[j], s.idx[i]
 }
+
+func isNaN(v apl.Value) bool {
+	f, ok := v.(numbers.Float)
+	return ok && math.IsNaN(float64(f))
+}
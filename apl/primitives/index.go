@@ -677,8 +677,8 @@ func tableIndex(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	for i, k := range cols {
 		key := keys[k].Copy()
 		d.K[i] = key
-		srccol := t.At(key).(apl.Uniform)
-		col := srccol.Make([]int{len(rows)})
+		srccol := t.At(key).(apl.Array)
+		col := apl.MakeArray(srccol, []int{len(rows)})
 		for n, m := range rows {
 			if err := col.Set(n, srccol.At(m).Copy()); err != nil {
 				return nil, err
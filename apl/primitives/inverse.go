@@ -0,0 +1,135 @@
+package primitives
+
+import (
+	"fmt"
+
+	"github.com/ktye/iv/apl"
+)
+
+// inverse returns a function that calls the primitive p as the inverse of another primitive.
+// If swap is true, the arguments of a dyadic call are exchanged:
+//	L+⍣¯1 R ←→ R-L
+func inverse(p string, swap bool) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
+	return func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		if swap && L != nil {
+			L, R = R, L
+		}
+		return apl.Primitive(p).Call(a, L, R)
+	}
+}
+
+// decodeInverse encodes R: L⊥⍣¯1 R ←→ L⊤R
+// The domain of decode has converted a scalar R to a single element vector, which is reverted.
+// A scalar radix L is extended to the number of digits needed for the largest value of R:
+//	2⊥⍣¯1⊢5 ←→ 2 2 2⊤5
+func decodeInverse(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if ar, ok := R.(apl.Array); ok && ar.Size() == 1 && len(ar.Shape()) == 1 {
+		R = ar.At(0)
+	}
+	if al, ok := L.(apl.Array); ok && al.Size() == 1 {
+		n, err := radixDigits(a, al.At(0), R)
+		if err != nil {
+			return nil, err
+		}
+		L, err = apl.Primitive("⍴").Call(a, apl.Int(n), al.At(0))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return apl.Primitive("⊤").Call(a, L, R)
+}
+
+// radixDigits returns the number of digits in the radix b,
+// that are needed to represent the largest magnitude of R.
+func radixDigits(a *apl.Apl, b, R apl.Value) (int, error) {
+	if le, err := apl.Primitive("≤").Call(a, b, apl.Int(1)); err != nil {
+		return 0, err
+	} else if le == apl.Bool(true) {
+		return 1, nil
+	}
+	x, err := apl.Primitive("|").Call(a, nil, R)
+	if err != nil {
+		return 0, err
+	}
+	for n := 1; ; n++ {
+		if x, err = apl.Primitive("÷").Call(a, x, b); err != nil {
+			return 0, err
+		}
+		if x, err = apl.Primitive("⌊").Call(a, nil, x); err != nil {
+			return 0, err
+		}
+		z, err := apl.Primitive("=").Call(a, x, apl.Int(0))
+		if err != nil {
+			return 0, err
+		}
+		if allTrue(z) {
+			return n, nil
+		}
+	}
+}
+
+// allTrue returns true, if v is true or an array with only true values.
+func allTrue(v apl.Value) bool {
+	ar, ok := v.(apl.Array)
+	if ok == false {
+		return v == apl.Bool(true)
+	}
+	for i := 0; i < ar.Size(); i++ {
+		if ar.At(i) != apl.Bool(true) {
+			return false
+		}
+	}
+	return true
+}
+
+// pitimesInverse divides by pi: ○⍣¯1 R ←→ R÷○1
+func pitimesInverse(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	pi, err := apl.Primitive("○").Call(a, nil, apl.Int(1))
+	if err != nil {
+		return nil, err
+	}
+	return apl.Primitive("÷").Call(a, R, pi)
+}
+
+// circularInverse applies the inverse circular function: L○⍣¯1 R ←→ (-L)○R
+func circularInverse(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	nl, err := apl.Primitive("-").Call(a, nil, L)
+	if err != nil {
+		return nil, err
+	}
+	return apl.Primitive("○").Call(a, nl, R)
+}
+
+// rotateInverse rotates in the opposite direction: L⌽⍣¯1 R ←→ (-L)⌽R
+// The domain of rotate has converted a scalar L to an index array, which is reverted.
+func rotateInverse(p string) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
+	return func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		if ia, ok := L.(apl.IntArray); ok && len(ia.Ints) == 1 && len(ia.Dims) == 1 {
+			L = apl.Int(ia.Ints[0])
+		}
+		nl, err := apl.Primitive("-").Call(a, nil, L)
+		if err != nil {
+			return nil, err
+		}
+		return apl.Primitive(p).Call(a, nl, R)
+	}
+}
+
+// transposeInverse applies the inverse permutation: L⍉⍣¯1 R ←→ (⍋L)⍉R
+// L must be a permutation of the axes.
+func transposeInverse(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	ia := L.(apl.IntArray)
+	seen := make([]bool, len(ia.Ints))
+	for _, n := range ia.Ints {
+		n -= a.Origin
+		if n < 0 || n >= len(seen) || seen[n] {
+			return nil, fmt.Errorf("transpose: only a permutation of the axes has an inverse")
+		}
+		seen[n] = true
+	}
+	gl, err := apl.Primitive("⍋").Call(a, nil, L)
+	if err != nil {
+		return nil, err
+	}
+	return apl.Primitive("⍉").Call(a, gl, R)
+}
@@ -49,6 +49,9 @@ func interval(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 	if n == 0 {
 		return apl.EmptyArray{}, nil
 	}
+	if err := a.CheckSize(n); err != nil {
+		return nil, err
+	}
 	ar := apl.IntArray{
 		Ints: make([]int, n),
 		Dims: []int{n},
@@ -227,8 +230,8 @@ func intervalindex(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 
 // IsEqual compares if the values are equal.
 // If they are numbers of different type, they are converted before comparison.
+// Floats are compared with the comparison tolerance ⎕CT.
 func isEqual(a *apl.Apl, x, y apl.Value) bool {
-	// TODO: should we use CT (comparison tolerance)?
 	if x == y {
 		return true
 	}
@@ -240,12 +243,8 @@ func isEqual(a *apl.Apl, x, y apl.Value) bool {
 		return false
 	}
 	if xn, yn, err := a.Tower.SameType(xn.(apl.Number), yn.(apl.Number)); err == nil {
-		if eq, ok := xn.(equaler); ok {
-			if iseq, ok := eq.Equals(yn); ok {
-				return bool(iseq)
-			}
-		} else {
-			return xn == yn
+		if iseq, ok := equals(a, xn, yn); ok {
+			return bool(iseq)
 		}
 	}
 	return false
@@ -0,0 +1,89 @@
+package primitives
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ktye/iv/apl"
+	"github.com/ktye/iv/apl/numbers"
+	"github.com/ktye/iv/apl/operators"
+)
+
+// TestLimits should be in apl where EvalContext is defined.
+// But there are no primitives available.
+func TestLimits(t *testing.T) {
+	testCases := []struct {
+		in     string
+		limits apl.Limits
+		exp    error
+	}{
+		{"{∇⍵}0", apl.Limits{Steps: 1000}, apl.ErrStepLimit},
+		{"{⍵+1}⍣≡0", apl.Limits{Steps: 1000, Power: 1e9}, apl.ErrStepLimit},
+		{"{⍵+1}⍣≡0", apl.Limits{Time: 10 * time.Millisecond, Power: 1e9}, apl.ErrTimeLimit},
+		{"f←{⍵≤0:0⋄1+f ⍵-1}⋄f 100", apl.Limits{Depth: 50}, apl.ErrDepthLimit},
+		{"f←{⍵≤0:0⋄1+f ⍵-1}⋄f 10", apl.Limits{Depth: 50}, nil},
+		{"⍳1000", apl.Limits{Size: 100}, apl.ErrSizeLimit},
+		{"10 20⍴1", apl.Limits{Size: 100}, apl.ErrSizeLimit},
+		{"{0::0⋄{∇⍵}⍵}0", apl.Limits{Steps: 1000}, apl.ErrStepLimit}, // limits cannot be trapped
+		{"+/⍳100", apl.Limits{Steps: 1000, Size: 100}, nil},
+		{"X←⍳60⋄X,X", apl.Limits{Size: 100}, apl.ErrSizeLimit},
+		{"1000/1", apl.Limits{Size: 100}, apl.ErrSizeLimit},
+		{"X←⍳20⋄X∘.+X", apl.Limits{Size: 100}, apl.ErrSizeLimit},
+	}
+	for _, tc := range testCases {
+		var buf bytes.Buffer
+		a := apl.New(&buf)
+		numbers.Register(a)
+		Register(a)
+		operators.Register(a)
+		a.Limits = tc.limits
+
+		p, err := a.Parse(tc.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := a.EvalContext(context.Background(), p); err != tc.exp {
+			t.Fatalf("%s: expected %v, got %v", tc.in, tc.exp, err)
+		}
+	}
+}
+
+// TestEvalLimits tests that the limits also apply without a context.
+func TestEvalLimits(t *testing.T) {
+	var buf bytes.Buffer
+	a := apl.New(&buf)
+	numbers.Register(a)
+	Register(a)
+	operators.Register(a)
+	a.Limits = apl.Limits{Steps: 1000}
+
+	if err := a.ParseAndEval("{∇⍵}0"); err != apl.ErrStepLimit {
+		t.Fatalf("expected %v, got %v", apl.ErrStepLimit, err)
+	}
+	if err := a.ParseAndEval("+/⍳100"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEvalContextCancel(t *testing.T) {
+	var buf bytes.Buffer
+	a := apl.New(&buf)
+	numbers.Register(a)
+	Register(a)
+	operators.Register(a)
+
+	p, err := a.Parse("{∇⍵}0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if err := a.EvalContext(ctx, p); err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+}
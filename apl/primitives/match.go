@@ -36,15 +36,29 @@ func init() {
 }
 
 // depth reports the level of nesting.
-// Nested arrays are not supported, so depth is always 1 for arrays and 0 for scalars.
+// It is 0 for scalars and 1 for simple arrays.
+// Nested arrays have the depth of the deepest item plus 1.
 func depth(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 	if l, ok := R.(apl.List); ok {
 		return apl.Int(l.Depth()), nil
 	}
-	if _, ok := R.(apl.Array); ok {
-		return apl.Int(1), nil
+	return apl.Int(arrayDepth(R)), nil
+}
+
+func arrayDepth(v apl.Value) int {
+	ar, ok := v.(apl.Array)
+	if ok == false {
+		return 0
+	} else if apl.IsNested(v) == false {
+		return 1
 	}
-	return apl.Int(0), nil
+	max := 0
+	for i := 0; i < ar.Size(); i++ {
+		if d := arrayDepth(ar.At(i)); d > max {
+			max = d
+		}
+	}
+	return 1 + max
 }
 
 // tally returns the number of major cells of R.
@@ -62,7 +76,7 @@ func tally(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 	}
 	shape := ar.Shape()
 	if len(shape) == 0 {
-		return apl.Int(0), nil
+		return apl.Int(ar.Size()), nil // empty array or enclosed scalar
 	}
 	return apl.Int(shape[0]), nil
 }
@@ -81,7 +95,7 @@ func match(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		sr := ar.Shape()
 		if len(sr) != len(sl) {
 			return apl.Bool(false), nil
-		} else if len(sr) == 0 {
+		} else if len(sr) == 0 && (ar.Size() == 0 || al.Size() == 0) {
 			// Empty arrays must have the same type.
 			if reflect.TypeOf(ar) == reflect.TypeOf(al) {
 				return apl.Bool(true), nil
@@ -96,7 +110,19 @@ func match(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		}
 		feq := arith2("=", compare("="))
 		for i := 0; i < ar.Size(); i++ {
-			if iseq, err := feq(a, ar.At(i), al.At(i)); err != nil {
+			x, y := ar.At(i), al.At(i)
+			_, xa := x.(apl.Array)
+			_, ya := y.(apl.Array)
+			if xa || ya {
+				// Items of nested arrays.
+				if m, err := match(a, y, x); err != nil {
+					return nil, err
+				} else if m.(apl.Bool) == false {
+					return apl.Bool(false), nil
+				}
+				continue
+			}
+			if iseq, err := feq(a, x, y); err != nil {
 				return nil, err
 			} else if iseq.(apl.Bool) == false {
 				return apl.Bool(false), nil
@@ -0,0 +1,279 @@
+package primitives
+
+import (
+	"fmt"
+
+	"github.com/ktye/iv/apl"
+	. "github.com/ktye/iv/apl/domain"
+)
+
+func init() {
+	register(primitive{
+		symbol: "⊆",
+		doc:    "nest",
+		Domain: Monadic(Nested(nil)),
+		fn:     nest,
+	})
+	register(primitive{
+		symbol: "⊆",
+		doc:    "partition",
+		Domain: Dyadic(Split(ToIndexArray(nil), ToVector(nil))),
+		fn:     partitionArray(false),
+	})
+	register(primitive{
+		symbol: "⊆",
+		doc:    "partition string",
+		Domain: Dyadic(Split(ToIndexArray(nil), IsString(nil))),
+		fn:     partitionString(false),
+	})
+	register(primitive{
+		symbol: "⊆",
+		doc:    "partition channel",
+		Domain: Dyadic(Split(IsChannel(nil), IsChannel(nil))),
+		fn:     partitionChannel(false),
+	})
+	register(primitive{
+		symbol: "⊂",
+		doc:    "partitioned enclose",
+		Domain: Dyadic(Split(ToIndexArray(nil), ToVector(nil))),
+		fn:     partitionArray(true),
+	})
+	register(primitive{
+		symbol: "⊂",
+		doc:    "partitioned enclose string",
+		Domain: Dyadic(Split(ToIndexArray(nil), IsString(nil))),
+		fn:     partitionString(true),
+	})
+	register(primitive{
+		symbol: "⊂",
+		doc:    "partitioned enclose channel",
+		Domain: Dyadic(Split(IsChannel(nil), IsChannel(nil))),
+		fn:     partitionChannel(true),
+	})
+}
+
+// nest encloses a simple array, if nested arrays are enabled.
+// Nested arrays and scalars are returned unchanged.
+func nest(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	if _, ok := R.(apl.Array); ok && apl.IsNested(R) == false {
+		return apl.Enclose(R), nil
+	}
+	return R, nil
+}
+
+// partitioner decides for each marker of L, if the current segment ends
+// before the element and if the element is kept.
+type partitioner func(k int) (cut, keep bool)
+
+// markers returns the partitioner for partition ⊆ or for partitioned enclose ⊂.
+//
+// Partition: a new segment starts where L is larger than its left neighbour.
+// Elements where L is 0 are dropped.
+//
+// Partitioned enclose: a new segment starts where L is not 0.
+// Elements before the first segment are dropped.
+func markers(enclose bool) partitioner {
+	if enclose {
+		started := false
+		return func(k int) (bool, bool) {
+			if k != 0 {
+				started = true
+				return true, true
+			}
+			return false, started
+		}
+	}
+	prev := 0
+	return func(k int) (bool, bool) {
+		cut := k == 0 || k > prev
+		prev = k
+		return cut, k != 0
+	}
+}
+
+// segments returns the indexes of the elements of each segment.
+// A scalar L is extended.
+func segments(l apl.IntArray, n int, p partitioner) ([][]int, error) {
+	if len(l.Dims) > 1 {
+		return nil, fmt.Errorf("partition: L must be a vector")
+	} else if l.Size() != 1 && l.Size() != n {
+		return nil, fmt.Errorf("partition: length error: %d != %d", l.Size(), n)
+	}
+	var segs [][]int
+	var seg []int
+	for i := 0; i < n; i++ {
+		k := l.Ints[0]
+		if l.Size() > 1 {
+			k = l.Ints[i]
+		}
+		cut, keep := p(k)
+		if cut && len(seg) > 0 {
+			segs = append(segs, seg)
+			seg = nil
+		}
+		if keep {
+			seg = append(seg, i)
+		}
+	}
+	if len(seg) > 0 {
+		segs = append(segs, seg)
+	}
+	return segs, nil
+}
+
+// partitionResult returns the segments as a List,
+// or as a nested vector if nested arrays are enabled.
+func partitionResult(a *apl.Apl, v []apl.Value) apl.Value {
+	if a.Nested {
+		return a.UnifyArray(apl.MixedArray{Dims: []int{len(v)}, Values: v})
+	}
+	return apl.List(v)
+}
+
+// partitionArray splits the vector R into segments and returns them as a List.
+//	1 1 2 2 0 3⊆⍳6
+//	1 0 1 0 0⊂⍳5
+// Each segment is a vector of the same type as R, segments of a List are Lists.
+func partitionArray(enclose bool) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
+	return func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		r := R.(apl.Array)
+		segs, err := segments(L.(apl.IntArray), r.Size(), markers(enclose))
+		if err != nil {
+			return nil, err
+		}
+		lst, isList := R.(apl.List)
+		res := make([]apl.Value, len(segs))
+		for i, seg := range segs {
+			if isList {
+				l := make(apl.List, len(seg))
+				for k, n := range seg {
+					l[k] = lst[n].Copy()
+				}
+				res[i] = l
+				continue
+			}
+			v := apl.MakeArray(r, []int{len(seg)})
+			for k, n := range seg {
+				if err := v.Set(k, r.At(n).Copy()); err != nil {
+					return nil, err
+				}
+			}
+			res[i] = v
+		}
+		return partitionResult(a, res), nil
+	}
+}
+
+// partitionString splits the characters of the string R into segments of strings.
+// L has one element for each character:
+//	(" "≠⊃S)⊆S←"alpha beta  gamma"
+func partitionString(enclose bool) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
+	return func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		r := []rune(string(R.(apl.String)))
+		segs, err := segments(L.(apl.IntArray), len(r), markers(enclose))
+		if err != nil {
+			return nil, err
+		}
+		res := make([]apl.Value, len(segs))
+		for i, seg := range segs {
+			res[i] = apl.String(r[seg[0] : seg[len(seg)-1]+1])
+		}
+		return partitionResult(a, res), nil
+	}
+}
+
+// partitionChannel is the streaming partition.
+// It reads a marker from L for each value from R and
+// sends a segment over the returned channel, each time a segment is complete.
+//	(<¨1 0 1 0 0)⊂<¨⍳5
+// The last segment is sent, when one of the input channels is closed.
+func partitionChannel(enclose bool) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
+	return func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		l := L.(apl.Channel)
+		r := R.(apl.Channel)
+		p := markers(enclose)
+		c := apl.NewChannel()
+		done := a.Done()
+		a = a.Fork()
+		go func() {
+			defer close(c[0])
+			var seg []apl.Value
+			send := func(v apl.Value) bool {
+				select {
+				case <-done:
+					return false
+				case _, ok := <-c[1]:
+					return ok
+				case c[0] <- v:
+					return true
+				}
+			}
+			flush := func() bool {
+				if len(seg) == 0 {
+					return true
+				}
+				var v apl.Value = apl.MixedArray{Dims: []int{len(seg)}, Values: seg}
+				if a.Nested == false && apl.IsNested(v) {
+					v = apl.List(seg)
+				} else {
+					v = a.UnifyArray(v.(apl.Array))
+				}
+				seg = nil
+				return send(v)
+			}
+			hangup := func() {
+				close(l[1])
+				close(r[1])
+			}
+			for {
+				var v, m apl.Value
+				var ok bool
+				select {
+				case <-done:
+					hangup()
+					return
+				case _, ok = <-c[1]:
+					if ok == false {
+						hangup()
+						return
+					}
+					continue
+				case v, ok = <-r[0]:
+				}
+				if ok == false {
+					close(l[1])
+					flush()
+					return
+				}
+				if m, ok = <-l[0]; ok == false {
+					close(r[1])
+					flush()
+					return
+				}
+				k, ok := marker(m)
+				if ok == false {
+					send(apl.Error{E: fmt.Errorf("partition: marker must be an integer: %T", m)})
+					hangup()
+					return
+				}
+				cut, keep := p(k)
+				if cut && flush() == false {
+					hangup()
+					return
+				}
+				if keep {
+					seg = append(seg, v)
+				}
+			}
+		}()
+		return c, nil
+	}
+}
+
+// marker converts a value read from a marker channel to an int.
+func marker(v apl.Value) (int, bool) {
+	if n, ok := v.(apl.Number); ok {
+		return n.ToIndex()
+	}
+	return 0, false
+}
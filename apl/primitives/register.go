@@ -33,6 +33,7 @@ type primitive struct {
 	doc    string
 	fn     func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error)
 	sel    func(*apl.Apl, apl.Value, apl.Value) (apl.IntArray, error)
+	inv    func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error)
 }
 
 func (p primitive) Call(a *apl.Apl, L, R apl.Value) (apl.Value, error) { return p.fn(a, L, R) }
@@ -42,4 +43,10 @@ func (p primitive) Select(a *apl.Apl, L, R apl.Value) (apl.IntArray, error) {
 	}
 	return p.sel(a, L, R)
 }
+func (p primitive) CallInverse(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	if p.inv == nil {
+		return nil, fmt.Errorf("primitive %s has no inverse", p.symbol)
+	}
+	return p.inv(a, L, R)
+}
 func (p primitive) Doc() string { return p.doc }
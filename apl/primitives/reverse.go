@@ -14,6 +14,7 @@ func init() {
 		Domain: Monadic(nil),
 		fn:     revLast,
 		sel:    selection(revLast),
+		inv:    inverse("⌽", false),
 	})
 	register(primitive{
 		symbol: "⊖",
@@ -21,6 +22,7 @@ func init() {
 		Domain: Monadic(nil),
 		fn:     revFirst,
 		sel:    selection(revFirst),
+		inv:    inverse("⊖", false),
 	})
 	// TODO reverse with axis
 
@@ -30,6 +32,7 @@ func init() {
 		Domain: Dyadic(Split(ToIndexArray(nil), nil)),
 		fn:     rotLast,
 		sel:    selection(rotLast),
+		inv:    rotateInverse("⌽"),
 	})
 	register(primitive{
 		symbol: "⊖",
@@ -37,6 +40,7 @@ func init() {
 		Domain: Dyadic(Split(ToIndexArray(nil), nil)),
 		fn:     rotFirst,
 		sel:    selection(rotFirst),
+		inv:    rotateInverse("⊖"),
 	})
 	// TODO rotate with axis
 }
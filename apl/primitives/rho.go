@@ -75,6 +75,9 @@ func rho2(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	l := L.(apl.IntArray)
 	shape := make([]int, len(l.Ints))
 	copy(shape, l.Ints)
+	if err := a.CheckSize(apl.Prod(shape)); err != nil {
+		return nil, err
+	}
 	if rs, ok := R.(apl.Reshaper); ok {
 		return rs.Reshape(shape), nil
 	}
@@ -0,0 +1,55 @@
+package primitives
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ktye/iv/apl"
+	"github.com/ktye/iv/apl/numbers"
+	"github.com/ktye/iv/apl/operators"
+	aplstrings "github.com/ktye/iv/apl/strings"
+)
+
+func TestSandbox(t *testing.T) {
+	sandbox := &apl.Sandbox{
+		Primitives: []string{"+", "⍴", "⍳ interval, index generater, progression"},
+		Operators:  []string{"/"},
+		Packages:   []string{"s→toupper"},
+	}
+	testCases := []struct {
+		in, exp string
+	}{
+		{"+/⍳3", "6"},
+		{"A←2 3⍴⍳6⋄+/A", "6 15"},
+		{`s→toupper "a"`, "A"},
+		{"1 2 3⍳2", "fail: sandbox: primitive ⍳ (index of, first occurrence) is not allowed"},
+		{"-3", "fail: sandbox: primitive - is not allowed"},
+		{"+¨1 2", "fail: sandbox: operator ¨ is not allowed"},
+		{`s→tolower "A"`, "fail: sandbox: s→tolower is not allowed"},
+		{"⍎'-3'", "fail: sandbox: primitive ⍎ is not allowed"},
+		{`⎕eval ("primitive";"-";)`, "fail: sandbox: primitive - is not allowed"},
+	}
+	for _, tc := range testCases {
+		var buf bytes.Buffer
+		a := apl.New(&buf)
+		numbers.Register(a)
+		Register(a)
+		operators.Register(a)
+		aplstrings.Register(a, "s")
+		a.Sandbox = sandbox
+
+		err := a.ParseAndEval(tc.in)
+		if strings.HasPrefix(tc.exp, "fail:") {
+			if err == nil {
+				t.Fatalf("%s: should fail", tc.in)
+			} else if exp := strings.TrimPrefix(tc.exp, "fail: "); err.Error() != exp {
+				t.Fatalf("%s: expected error %q, got %q", tc.in, exp, err.Error())
+			}
+		} else if err != nil {
+			t.Fatalf("%s: %s", tc.in, err)
+		} else if got := strings.TrimSpace(buf.String()); got != tc.exp {
+			t.Fatalf("%s: expected %s, got %s", tc.in, tc.exp, got)
+		}
+	}
+}
@@ -11,12 +11,14 @@ func init() {
 		doc:    "left tack, same",
 		Domain: Monadic(nil),
 		fn:     same,
+		inv:    same,
 	})
 	register(primitive{
 		symbol: "⊢",
 		doc:    "right tack, same",
 		Domain: Monadic(nil),
 		fn:     same,
+		inv:    same,
 	})
 	register(primitive{
 		symbol: "⊣",
@@ -29,6 +31,7 @@ func init() {
 		doc:    "right tack, right argument",
 		Domain: Dyadic(nil),
 		fn:     right,
+		inv:    right,
 	})
 }
 
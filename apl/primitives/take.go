@@ -29,6 +29,12 @@ func init() {
 		Domain: Monadic(IsChannel(nil)),
 		fn:     takeChannel1,
 	})
+	register(primitive{
+		symbol: "↑",
+		doc:    "mix",
+		Domain: Monadic(Nested(IsArray(nil))),
+		fn:     mix,
+	})
 	register(primitive{
 		symbol: "↓",
 		doc:    "drop",
@@ -314,3 +320,58 @@ func closeChannel(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
 	R.(apl.Channel).Close()
 	return apl.Int(1), nil
 }
+
+// mix combines the items of a nested array to a simple array of higher rank,
+// if nested arrays are enabled.
+// The items are padded to a common shape, items with a lower rank are extended with leading axes of length 1:
+//	↑(1 2)(3 4 5)
+// A simple array is returned unchanged.
+func mix(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	if apl.IsNested(R) == false {
+		return R, nil
+	}
+	m := R.(apl.MixedArray)
+	var common []int
+	for _, v := range m.Values {
+		if ar, ok := v.(apl.Array); ok {
+			s := ar.Shape()
+			if d := len(s) - len(common); d > 0 {
+				common = append(make([]int, d), common...)
+			}
+			for n := range s {
+				k := len(common) - len(s) + n
+				if s[n] > common[k] {
+					common[k] = s[n]
+				}
+			}
+		}
+	}
+	if len(common) == 0 {
+		common = []int{0}
+	}
+	size := apl.Prod(common)
+	res := apl.NewMixed(append(apl.CopyShape(m), common...))
+	idx := apl.IntArray{Dims: []int{len(common)}, Ints: common}
+	for i, v := range m.Values {
+		ar, ok := v.(apl.Array)
+		if ok == false {
+			ar = a.UnifyArray(apl.MixedArray{Dims: []int{1}, Values: []apl.Value{v}})
+		}
+		shape := apl.CopyShape(ar)
+		for len(shape) < len(common) {
+			shape = append([]int{1}, shape...)
+		}
+		if rs, ok := ar.(apl.Reshaper); ok {
+			ar = rs.Reshape(shape).(apl.Array)
+		}
+		t, err := take(a, idx, ar)
+		if err != nil {
+			return nil, err
+		}
+		ar = t.(apl.Array)
+		for k := 0; k < size; k++ {
+			res.Values[i*size+k] = ar.At(k).Copy()
+		}
+	}
+	return a.UnifyArray(res), nil
+}
@@ -14,18 +14,21 @@ func init() {
 		Domain: Monadic(IsArray(nil)),
 		fn:     transpose,
 		sel:    selection(transpose),
+		inv:    inverse("⍉", false),
 	})
 	register(primitive{
 		symbol: "⍉",
 		doc:    "table from object, transpose, flip",
 		Domain: Monadic(IsObject(nil)),
 		fn:     transposeObject,
+		inv:    inverse("⍉", false),
 	})
 	register(primitive{
 		symbol: "⍉",
 		doc:    "dict from table, transpose, flip",
 		Domain: Monadic(IsTable(nil)),
 		fn:     transposeTable,
+		inv:    inverse("⍉", false),
 	})
 	register(primitive{
 		symbol: "⍉",
@@ -40,6 +43,7 @@ func init() {
 		Domain: Dyadic(Split(ToIndexArray(nil), IsArray(nil))),
 		fn:     transpose,
 		sel:    selection(transpose),
+		inv:    transposeInverse,
 	})
 }
 
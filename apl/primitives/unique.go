@@ -1,6 +1,8 @@
 package primitives
 
 import (
+	"fmt"
+
 	"github.com/ktye/iv/apl"
 	. "github.com/ktye/iv/apl/domain"
 )
@@ -18,6 +20,80 @@ func init() {
 		Domain: Dyadic(Split(ToVector(nil), ToVector(nil))),
 		fn:     union,
 	})
+	register(primitive{
+		symbol: "∩",
+		doc:    "intersection",
+		Domain: Dyadic(Split(ToVector(nil), ToVector(nil))),
+		fn:     intersection,
+	})
+	register(primitive{
+		symbol: "≠",
+		doc:    "unique mask, nub sieve",
+		Domain: Monadic(ToVector(nil)),
+		fn:     nubSieve,
+	})
+
+	// Set functions on lists, rows of matrices and rows of tables.
+	for _, d := range []struct {
+		SingleDomain
+		name string
+	}{
+		{IsList(nil), "list"},
+		{IsMatrix(nil), "rows"},
+		{IsTable(nil), "table rows"},
+	} {
+		register(primitive{
+			symbol: "∪",
+			doc:    "unique " + d.name,
+			Domain: Monadic(d.SingleDomain),
+			fn:     uniqueCells,
+		})
+		register(primitive{
+			symbol: "≠",
+			doc:    "nub sieve " + d.name,
+			Domain: Monadic(d.SingleDomain),
+			fn:     nubSieve,
+		})
+		register(primitive{
+			symbol: "∪",
+			doc:    "union " + d.name,
+			Domain: Dyadic(Split(d.SingleDomain, d.SingleDomain)),
+			fn:     unionCells,
+		})
+		register(primitive{
+			symbol: "∩",
+			doc:    "intersection " + d.name,
+			Domain: Dyadic(Split(d.SingleDomain, d.SingleDomain)),
+			fn:     intersection,
+		})
+		register(primitive{
+			symbol: "~",
+			doc:    "without " + d.name,
+			Domain: Dyadic(Split(d.SingleDomain, d.SingleDomain)),
+			fn:     withoutCells,
+		})
+	}
+
+	// Set functions on dict keys.
+	dict := IsObject(Not(IsTable(nil)))
+	register(primitive{
+		symbol: "∪",
+		doc:    "union dict keys",
+		Domain: Dyadic(Split(dict, dict)),
+		fn:     dictUnion,
+	})
+	register(primitive{
+		symbol: "∩",
+		doc:    "intersection dict keys",
+		Domain: Dyadic(Split(dict, dict)),
+		fn:     dictIntersection,
+	})
+	register(primitive{
+		symbol: "~",
+		doc:    "without dict keys",
+		Domain: Dyadic(Split(dict, nil)),
+		fn:     dictWithout,
+	})
 }
 
 // unique: R is a vector.
@@ -80,3 +156,340 @@ func union(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	}
 	return a.UnifyArray(apl.MixedArray{Dims: []int{len(values)}, Values: values}), nil
 }
+
+// intersection returns the elements of L that are also in R.
+// Duplicates in L are kept.
+// L and R are vectors, lists, matrices or tables.
+// For matrices and tables, rows are compared.
+func intersection(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	l, r, err := cellPair(a, L, R)
+	if err != nil {
+		return nil, err
+	}
+	var idx []int
+	for i := 0; i < l.n; i++ {
+		if r.find(a, l, i, r.n) >= 0 {
+			idx = append(idx, i)
+		}
+	}
+	return l.pick(idx)
+}
+
+// nubSieve returns a boolean vector that is 1 at the first occurrence of each element of R.
+// For matrices and tables, it marks the first occurrence of each row.
+//	∪R ←→ (≠R)/R
+func nubSieve(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	c, err := cellsOf(a, R, nil)
+	if err != nil {
+		return nil, err
+	}
+	b := apl.BoolArray{Dims: []int{c.n}, Bools: make([]bool, c.n)}
+	for i := 0; i < c.n; i++ {
+		b.Bools[i] = c.find(a, c, i, i) < 0
+	}
+	return b, nil
+}
+
+// uniqueCells returns the unique elements of a list or the unique rows of a matrix or table.
+func uniqueCells(a *apl.Apl, _, R apl.Value) (apl.Value, error) {
+	c, err := cellsOf(a, R, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.pick(c.unique(a, nil))
+}
+
+// unionCells returns the unique elements of L followed by the unique elements of R that are not in L.
+// L and R are both lists, matrices or tables.
+func unionCells(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	l, r, err := cellPair(a, L, R)
+	if err != nil {
+		return nil, err
+	}
+	lv, err := l.pick(l.unique(a, nil))
+	if err != nil {
+		return nil, err
+	}
+	rv, err := r.pick(r.unique(a, &l))
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := lv.(apl.List); ok {
+		return catenateLists(a, lv, rv)
+	}
+	return catenateFirst(a, lv, rv)
+}
+
+// withoutCells returns the elements of L that are not in R.
+// L and R are both lists, matrices or tables.
+func withoutCells(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	l, r, err := cellPair(a, L, R)
+	if err != nil {
+		return nil, err
+	}
+	var idx []int
+	for i := 0; i < l.n; i++ {
+		if r.find(a, l, i, r.n) < 0 {
+			idx = append(idx, i)
+		}
+	}
+	return l.pick(idx)
+}
+
+// cells are the elements of a vector or a list, or the rows of a matrix or a table.
+// The set functions compare them as a whole.
+type cells struct {
+	n    int
+	cell func(int) []apl.Value
+	elem func(int) apl.Value // element of a vector or list, nil for rows
+	pick func([]int) (apl.Value, error)
+	keys []apl.Value // column names of a table
+	cols int         // number of columns of a matrix
+}
+
+// find returns the index of the first cell before n that matches cell i of x, or -1.
+// Elements of vectors and lists are compared directly, without building a cell for each.
+func (c cells) find(a *apl.Apl, x cells, i, n int) int {
+	if c.elem != nil && x.elem != nil {
+		v := x.elem(i)
+		for k := 0; k < n; k++ {
+			if sameValue(a, v, c.elem(k)) {
+				return k
+			}
+		}
+		return -1
+	}
+	v := x.cell(i)
+	for k := 0; k < n; k++ {
+		if sameCell(a, v, c.cell(k)) {
+			return k
+		}
+	}
+	return -1
+}
+
+// unique returns the indexes of the first occurrence of each cell,
+// that is not contained in other.
+func (c cells) unique(a *apl.Apl, other *cells) []int {
+	var idx []int
+	for i := 0; i < c.n; i++ {
+		if c.find(a, c, i, i) < 0 && (other == nil || other.find(a, c, i, other.n) < 0) {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// sameCell compares two cells element by element with match.
+func sameCell(a *apl.Apl, x, y []apl.Value) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if sameValue(a, x[i], y[i]) == false {
+			return false
+		}
+	}
+	return true
+}
+
+// sameValue compares two elements with match.
+// Scalars are compared with isEqual directly.
+func sameValue(a *apl.Apl, x, y apl.Value) bool {
+	if _, ok := x.(apl.Array); ok == false {
+		if _, ok := y.(apl.Array); ok == false {
+			return isEqual(a, x, y)
+		}
+		return false
+	}
+	m, err := match(a, x, y)
+	return err == nil && m.(apl.Bool) == true
+}
+
+// cellPair returns the cells of both arguments.
+// Matrices must have the same number of columns and tables the same column names.
+// The columns of the right table are taken in the order of the left.
+func cellPair(a *apl.Apl, L, R apl.Value) (cells, cells, error) {
+	l, err := cellsOf(a, L, nil)
+	if err != nil {
+		return l, l, err
+	}
+	r, err := cellsOf(a, R, l.keys)
+	if err != nil {
+		return l, r, err
+	}
+	if l.cols != r.cols && l.n > 0 && r.n > 0 {
+		return l, r, fmt.Errorf("length error: matrices have %d and %d columns", l.cols, r.cols)
+	}
+	return l, r, nil
+}
+
+// cellsOf returns the cells of a vector, list, matrix or table.
+// If keys is not nil, the table columns are selected in this order.
+func cellsOf(a *apl.Apl, V apl.Value, keys []apl.Value) (cells, error) {
+	switch v := V.(type) {
+	case apl.Table:
+		return tableCells(a, v, keys)
+	case apl.List:
+		return cells{
+			n:    len(v),
+			cell: func(i int) []apl.Value { return []apl.Value{v[i]} },
+			elem: func(i int) apl.Value { return v[i] },
+			pick: func(idx []int) (apl.Value, error) {
+				l := make(apl.List, len(idx))
+				for i, n := range idx {
+					l[i] = v[n].Copy()
+				}
+				return l, nil
+			},
+		}, nil
+	case apl.Array:
+		shape := v.Shape()
+		if v.Size() == 0 || len(shape) == 1 {
+			return cells{
+				n:    v.Size(),
+				cell: func(i int) []apl.Value { return []apl.Value{v.At(i)} },
+				elem: v.At,
+				pick: func(idx []int) (apl.Value, error) {
+					if len(idx) == 0 {
+						return apl.EmptyArray{}, nil
+					}
+					values := make([]apl.Value, len(idx))
+					for i, n := range idx {
+						values[i] = v.At(n).Copy()
+					}
+					return a.UnifyArray(apl.MixedArray{Dims: []int{len(values)}, Values: values}), nil
+				},
+			}, nil
+		} else if len(shape) != 2 {
+			return cells{}, fmt.Errorf("set function: rank must be 1 or 2: %d", len(shape))
+		}
+		cols := shape[1]
+		return cells{
+			n:    shape[0],
+			cols: cols,
+			cell: func(i int) []apl.Value {
+				row := make([]apl.Value, cols)
+				for k := range row {
+					row[k] = v.At(i*cols + k)
+				}
+				return row
+			},
+			pick: func(idx []int) (apl.Value, error) {
+				res := apl.MakeArray(v, []int{len(idx), cols})
+				for i, n := range idx {
+					for k := 0; k < cols; k++ {
+						if err := res.Set(i*cols+k, v.At(n*cols+k).Copy()); err != nil {
+							return nil, err
+						}
+					}
+				}
+				return res, nil
+			},
+		}, nil
+	}
+	return cells{}, fmt.Errorf("set function: unsupported argument: %T", V)
+}
+
+// tableCells returns the rows of a table.
+func tableCells(a *apl.Apl, t apl.Table, keys []apl.Value) (cells, error) {
+	if keys == nil {
+		keys = t.Keys()
+	} else if len(keys) != len(t.Keys()) {
+		return cells{}, fmt.Errorf("set function: tables have different columns")
+	}
+	cols := make([]apl.Array, len(keys))
+	for i, k := range keys {
+		col, ok := t.At(k).(apl.Array)
+		if ok == false {
+			return cells{}, fmt.Errorf("set function: tables have different columns")
+		}
+		cols[i] = col
+	}
+	return cells{
+		n:    t.Rows,
+		keys: keys,
+		cell: func(i int) []apl.Value {
+			row := make([]apl.Value, len(cols))
+			for k, col := range cols {
+				row[k] = col.At(i)
+			}
+			return row
+		},
+		pick: func(idx []int) (apl.Value, error) {
+			d := apl.Dict{K: make([]apl.Value, len(keys)), M: make(map[apl.Value]apl.Value)}
+			for i, k := range keys {
+				col := apl.MakeArray(cols[i], []int{len(idx)})
+				for n, m := range idx {
+					if err := col.Set(n, cols[i].At(m).Copy()); err != nil {
+						return nil, err
+					}
+				}
+				d.K[i] = k.Copy()
+				d.M[k.Copy()] = col
+			}
+			return apl.Table{Dict: &d, Rows: len(idx)}, nil
+		},
+	}, nil
+}
+
+// dictUnion returns a dict with the keys of L followed by the keys of R that are not in L.
+// Values of common keys are taken from L.
+func dictUnion(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	l, r := L.(apl.Object), R.(apl.Object)
+	d := apl.Dict{M: make(map[apl.Value]apl.Value)}
+	for _, o := range []apl.Object{l, r} {
+		for _, k := range o.Keys() {
+			if _, ok := d.M[k]; ok == false {
+				d.K = append(d.K, k.Copy())
+				d.M[k.Copy()] = o.At(k).Copy()
+			}
+		}
+	}
+	return &d, nil
+}
+
+// dictIntersection returns a dict with the keys of L that are also in R.
+// The values are taken from L.
+func dictIntersection(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	l, r := L.(apl.Object), R.(apl.Object)
+	d := apl.Dict{M: make(map[apl.Value]apl.Value)}
+	for _, k := range l.Keys() {
+		if r.At(k) != nil {
+			d.K = append(d.K, k.Copy())
+			d.M[k.Copy()] = l.At(k).Copy()
+		}
+	}
+	return &d, nil
+}
+
+// dictWithout returns a dict with the keys of L that are not in R.
+// R is a dict, a single key or a vector of keys.
+func dictWithout(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	l := L.(apl.Object)
+	var drop []apl.Value
+	if o, ok := R.(apl.Object); ok {
+		drop = o.Keys()
+	} else if ar, ok := R.(apl.Array); ok {
+		for i := 0; i < ar.Size(); i++ {
+			drop = append(drop, ar.At(i))
+		}
+	} else {
+		drop = []apl.Value{R}
+	}
+	d := apl.Dict{M: make(map[apl.Value]apl.Value)}
+	for _, k := range l.Keys() {
+		found := false
+		for _, x := range drop {
+			if isEqual(a, k, x) {
+				found = true
+				break
+			}
+		}
+		if found == false {
+			d.K = append(d.K, k.Copy())
+			d.M[k.Copy()] = l.At(k).Copy()
+		}
+	}
+	return &d, nil
+}
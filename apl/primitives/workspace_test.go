@@ -0,0 +1,131 @@
+package primitives
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ktye/iv/apl"
+	"github.com/ktye/iv/apl/big"
+	"github.com/ktye/iv/apl/numbers"
+	"github.com/ktye/iv/apl/operators"
+)
+
+func TestWorkspace(t *testing.T) {
+	newApl := func(w io.Writer) *apl.Apl {
+		a := apl.New(w)
+		numbers.Register(a)
+		big.Register(a, "")
+		Register(a)
+		operators.Register(a)
+		return a
+	}
+	program := []string{
+		"A←2 3⍴⍳6",
+		"F←1.5 2.25÷3",
+		"Z←1J2 3",
+		"S←'it''s'",
+		"T←2018.01.02T12.03.04.123456789",
+		"U←¯1s",
+		"D←`a`b#(1;2 3;)",
+		"L←(1;'x';2.5;(`k#\"v\");)",
+		"X←⍉`x`y#(1 2 3;4.5 5 6;)",
+		"E←⍳0",
+		"f←{⍺+⍵×¯2.5}",
+		"twice←{⍺⍺ ⍺⍺ ⍵}",
+		"g←{×⍨twice ⍵}",
+		"h←+/",
+		"e←⎕eval ⎕parse '{⍵+1}'",
+		"C←`inc`dbl#({⍵+1};{2×⍵};)",
+		"⎕IO←0",
+		"⎕PP←3",
+		"⎕CT←1E¯10",
+		"P←big→set 1",
+		"B←2*100",
+	}
+	var out bytes.Buffer
+	a := newApl(&out)
+	for _, s := range program {
+		if err := a.ParseAndEval(s); err != nil {
+			t.Fatalf("%s: %s", s, err)
+		}
+	}
+	if err := a.LoadPkg(strings.NewReader("N←10\ninc←{N+⍵}"), "p.apl", "p"); err != nil {
+		t.Fatal(err)
+	}
+
+	var ws bytes.Buffer
+	skipped, err := a.SaveWorkspace(&ws)
+	if err != nil {
+		t.Fatal(err)
+	} else if exp := []string{"e", "h"}; reflect.DeepEqual(skipped, exp) == false {
+		t.Fatalf("expected skipped %v, got %v", exp, skipped)
+	}
+
+	b := newApl(&out)
+	if err := b.ParseAndEval("Old←1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.LoadWorkspace(&ws); err != nil {
+		t.Fatal(err)
+	}
+	if v := b.Lookup("Old"); v != nil {
+		t.Fatalf("variable is not removed by load: %v", v)
+	}
+	for _, name := range []string{"A", "F", "Z", "S", "T", "U", "D", "L", "X", "E", "B", "C", "⎕IO", "⎕PP", "⎕CT"} {
+		x, y := a.Lookup(name), b.Lookup(name)
+		if reflect.TypeOf(x) != reflect.TypeOf(y) {
+			t.Fatalf("%s: expected type %T, got %T", name, x, y)
+		} else if xs, ys := x.String(a.Format), y.String(b.Format); xs != ys {
+			t.Fatalf("%s: expected %s, got %s", name, xs, ys)
+		}
+	}
+	if x, y := time.Time(a.Lookup("T").(numbers.Time)), time.Time(b.Lookup("T").(numbers.Time)); x.Equal(y) == false {
+		t.Fatalf("T: expected %v, got %v", x, y)
+	}
+	testCases := []struct {
+		in, exp string
+	}{
+		{"1 f 2", "¯4"},
+		{"g 3", "81"},
+		{"p→inc 1", "11"},
+		{"B+1", "1267650600228229401496703205377"},
+		{"c←C[`inc]⋄c 2", "3"},
+		{"d←C[`dbl]⋄d 2", "4"},
+	}
+	for _, tc := range testCases {
+		out.Reset()
+		if err := b.ParseAndEval(tc.in); err != nil {
+			t.Fatalf("%s: %s", tc.in, err)
+		} else if got := strings.TrimSpace(out.String()); got != tc.exp {
+			t.Fatalf("%s: expected %s, got %s", tc.in, tc.exp, got)
+		}
+	}
+}
+
+func TestWorkspaceCorrupt(t *testing.T) {
+	var out bytes.Buffer
+	a := apl.New(&out)
+	numbers.Register(a)
+	Register(a)
+	operators.Register(a)
+	if err := a.ParseAndEval("Old←1"); err != nil {
+		t.Fatal(err)
+	}
+	ws := `{"io":0,"pp":3,"vars":{"A":{"t":"bool","s":"1"},"B":{"t":"unknown","s":"x"}},"packages":{"p":{"vars":{"N":{"t":"bool","s":"1"}}}}}`
+	if err := a.LoadWorkspace(strings.NewReader(ws)); err == nil {
+		t.Fatal("expected error")
+	}
+	if v := a.Lookup("Old"); v == nil || v.String(a.Format) != "1" {
+		t.Fatalf("Old: expected 1, got %v", v)
+	} else if v := a.Lookup("A"); v != nil {
+		t.Fatalf("A: variable is loaded: %v", v)
+	} else if a.Origin != 1 || a.Format.PP != 0 {
+		t.Fatalf("settings are changed: ⎕IO=%d ⎕PP=%d", a.Origin, a.Format.PP)
+	} else if v := a.Lookup("p→N"); v != nil {
+		t.Fatalf("p→N: package is loaded: %v", v)
+	}
+}
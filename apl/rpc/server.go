@@ -11,6 +11,7 @@ import (
 
 // ListenAndServe puts APL into server mode.
 // It accepts a single connection at a time from anyone.
+// Set a.Sandbox to restrict the functions a client may call.
 func ListenAndServe(a *apl.Apl, addr string) {
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
@@ -0,0 +1,86 @@
+package apl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sandbox is an allow-list of the functionality, an interpreter may use.
+// If Apl.Sandbox is not nil, everything that is not listed is rejected.
+// Names of primitives, operators and packages are checked by the parser,
+// single overloads when the function is dispatched.
+//
+// Primitives and Operators contain symbols, which allows all overloads,
+// or the symbol followed by the documentation string of a single overload:
+//	"+"            all overloads of +
+//	"< read fd"    only the overload of < that reads from a file descriptor
+// The documentation strings are listed in REF.md or printed with Doc.
+//
+// Packages contain the name of a package to allow all of it's variables,
+// or a single variable with it's package prefix:
+//	"s"            the strings package
+//	"io→cd"        only cd from the io package
+//
+// Assignment is always allowed.
+type Sandbox struct {
+	Primitives []string
+	Operators  []string
+	Packages   []string
+}
+
+// checkSymbol is called by the parser and returns an error, if the primitive
+// function or operator symbol is not allowed at all.
+func (s *Sandbox) checkSymbol(symbol string, op bool) error {
+	if s == nil || symbol == "←" || symbol == "⍂" {
+		return nil
+	}
+	list, name := s.Primitives, "primitive"
+	if op {
+		list, name = s.Operators, "operator"
+	}
+	for _, v := range list {
+		if v == symbol || strings.HasPrefix(v, symbol+" ") {
+			return nil
+		}
+	}
+	return fmt.Errorf("sandbox: %s %s is not allowed", name, symbol)
+}
+
+// checkOverload is called at dispatch time with the handler
+// that accepted the arguments.
+func (s *Sandbox) checkOverload(symbol, doc string, op bool) error {
+	if s == nil || symbol == "←" || symbol == "⍂" {
+		return nil
+	}
+	list, name := s.Primitives, "primitive"
+	if op {
+		list, name = s.Operators, "operator"
+	}
+	if idx := strings.Index(doc, "\t"); idx != -1 {
+		doc = doc[:idx]
+	}
+	for _, v := range list {
+		if v == symbol || v == symbol+" "+doc {
+			return nil
+		}
+	}
+	return fmt.Errorf("sandbox: %s %s (%s) is not allowed", name, symbol, doc)
+}
+
+// checkPackage is called by the parser for identifiers with a package prefix.
+func (s *Sandbox) checkPackage(name string) error {
+	if s == nil {
+		return nil
+	}
+	idx := strings.Index(name, "→")
+	if idx == -1 {
+		return nil
+	}
+	pkg := name[:idx]
+	for _, v := range s.Packages {
+		if v == pkg || v == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("sandbox: %s is not allowed", name)
+}
@@ -11,10 +11,26 @@ import (
 	"unicode/utf8"
 )
 
+// Token is a scanned token.
+// Pos and End are the byte offsets of the token in the line.
+// End is 0 for tokens, that are inserted by commands.
+// Line is not set by the scanner, but can be used to number multiple lines.
 type Token struct {
-	T   Type
-	S   string
+	T    Type
+	S    string
+	Pos  int
+	End  int
+	Line int
+}
+
+// Error is a scan error at byte offset Pos of the line.
+type Error struct {
 	Pos int
+	Err error
+}
+
+func (e Error) Error() string {
+	return e.Err.Error()
 }
 
 type Type int
@@ -36,6 +52,7 @@ const (
 	Semicolon       // ;
 	Self            // ∇
 	Diamond         // ⋄
+	Trap            // ::
 )
 
 // Scanner can split APL input into tokens.
@@ -46,6 +63,7 @@ type Scanner struct {
 	symbols  map[rune]string
 	commands map[string]Command
 	pos      int
+	start    int // start of the current token
 	width    int
 }
 
@@ -92,13 +110,13 @@ func (s *Scanner) Scan(line string) ([]Token, error) {
 	s.width = 0
 	s.tokens = nil
 	for {
-		pos := s.pos
 		if t, err := s.nextToken(); err != nil {
-			return nil, err
+			return nil, Error{Pos: s.start, Err: err}
 		} else if t.T == Endl {
 			break
 		} else {
-			t.Pos = pos
+			t.Pos = s.start
+			t.End = s.pos
 			s.tokens = append(s.tokens, t)
 		}
 	}
@@ -144,6 +162,8 @@ func (t Token) String() string {
 		s = "P"
 	case Colon:
 		s = ":"
+	case Trap:
+		s = "::"
 	case Semicolon:
 		s = ";"
 	case Self:
@@ -169,6 +189,38 @@ func PrintTokens(t []Token) string {
 	return "[" + strings.Join(v, ",") + "]"
 }
 
+// Source formats the tokens as APL source, that scans to the same tokens.
+// Strings are always double quoted.
+func Source(t []Token) string {
+	var b strings.Builder
+	for i, tok := range t {
+		if i > 0 && separate(t[i-1], tok) {
+			b.WriteRune(' ')
+		}
+		switch tok.T {
+		case String:
+			b.WriteString(strconv.Quote(tok.S))
+		case Chars:
+			b.WriteString("'" + strings.Replace(tok.S, "'", "''", -1) + "'")
+		default:
+			b.WriteString(tok.S)
+		}
+	}
+	return b.String()
+}
+
+// separate returns true, if two adjacent tokens must be separated by whitespace.
+func separate(a, b Token) bool {
+	word := func(t Token) bool {
+		return t.T == Number || t.T == Identifier || t.T == String || t.T == Chars
+	}
+	dot := func(t Token) bool { return t.T == Symbol && t.S == "." }
+	if word(a) && word(b) {
+		return true
+	}
+	return (a.T == Number && dot(b)) || (dot(a) && b.T == Number)
+}
+
 func (s *Scanner) ReadRune() (rune, int, error) {
 	r, w := s.nextRune()
 	if r == -1 {
@@ -209,6 +261,7 @@ func (s *Scanner) peek() rune {
 
 func (s *Scanner) nextToken() (Token, error) {
 	for {
+		s.start = s.pos // whitespace is skipped
 		r, _ := s.nextRune()
 		if r == -1 {
 			return Token{T: Endl}, nil
@@ -257,6 +310,10 @@ func (s *Scanner) nextToken() (Token, error) {
 		case '}':
 			return Token{T: RightBrace, S: "}"}, nil
 		case ':':
+			if s.peek() == ':' {
+				s.nextRune()
+				return Token{T: Trap, S: "::"}, nil
+			}
 			return Token{T: Colon, S: ":"}, nil
 		case ';':
 			return Token{T: Semicolon, S: ";"}, nil
@@ -333,12 +390,17 @@ func (s *Scanner) scanString(quoteChar rune) (Token, error) {
 // An identifier may start with _ or a unicode letter.
 // Later characters may also be digits.
 // A → may be present within an identifier.
+// The operands of a lambda operator ⍺⍺ and ⍵⍵ are also identifiers.
 func (s *Scanner) scanIdentifier() (Token, error) {
 	var buf strings.Builder
 	first := true
 	arrow := false
 	for {
 		r, _ := s.nextRune()
+		if first && (r == '⍺' || r == '⍵') && s.peek() == r {
+			s.nextRune()
+			return Token{T: Identifier, S: string([]rune{r, r})}, nil
+		}
 		if AllowedInVarname(r, first) {
 			buf.WriteRune(r)
 		} else if r == '→' && arrow == false {
@@ -73,6 +73,20 @@ func TestScan(t *testing.T) {
 			Token{T: Number, S: "1"},
 			Token{T: RightBrace, S: "}"},
 		}},
+		{`{0::⍵:1}`, []Token{
+			Token{T: LeftBrace, S: "{"},
+			Token{T: Number, S: "0"},
+			Token{T: Trap, S: "::"},
+			Token{T: Symbol, S: "⍵"},
+			Token{T: Colon, S: ":"},
+			Token{T: Number, S: "1"},
+			Token{T: RightBrace, S: "}"},
+		}},
+		{`⍺⍺/⍺`, []Token{
+			Token{T: Identifier, S: "⍺⍺"},
+			Token{T: Symbol, S: "/"},
+			Token{T: Identifier, S: "⍺"},
+		}},
 	}
 
 	var scn Scanner
@@ -139,3 +153,60 @@ func TestScanString(t *testing.T) {
 		}
 	}
 }
+
+func TestSource(t *testing.T) {
+	symbols := make(map[rune]string)
+	for _, r := range "+×∘.,⍕" {
+		symbols[r] = string(r)
+	}
+	testCases := [][2]string{
+		{"{⍺+⍵×¯2.5}", "{⍺+⍵×¯2.5}"},
+		{"A 1 2", "A 1 2"},
+		{"1 2∘.×3", "1 2∘.×3"},
+		{"2 .5", "2 .5"},
+		{"'it''s' 'x',`a", `'it''s' 'x',"a"`},
+		{`"q\"x"`, `"q\"x"`},
+		{"{0::⍵⋄⍺⍺ ⍺}", "{0::⍵⋄⍺⍺ ⍺}"},
+	}
+	var scn Scanner
+	scn.SetSymbols(symbols)
+	for _, tc := range testCases {
+		tokens, err := scn.Scan(tc[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Source(tokens); got != tc[1] {
+			t.Fatalf("%s: expected %s, got %s", tc[0], tc[1], got)
+		}
+		// The source scans to the same tokens.
+		again, err := scn.Scan(tc[1])
+		if err != nil {
+			t.Fatal(err)
+		} else if PrintTokens(again) != PrintTokens(tokens) {
+			t.Fatalf("%s: tokens differ: %s %s", tc[0], PrintTokens(tokens), PrintTokens(again))
+		}
+	}
+}
+
+func TestPos(t *testing.T) {
+	var scn Scanner
+	scn.SetSymbols(map[rune]string{'+': "+"})
+	tokens, err := scn.Scan(" 12+ ⍺⍺ 'a b'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := [][2]int{{1, 3}, {3, 4}, {5, 11}, {12, 17}}
+	if len(tokens) != len(exp) {
+		t.Fatalf("expected %d tokens, got %d", len(exp), len(tokens))
+	}
+	for i, e := range exp {
+		if tokens[i].Pos != e[0] || tokens[i].End != e[1] {
+			t.Fatalf("token %d: expected %v, got %d %d", i, e, tokens[i].Pos, tokens[i].End)
+		}
+	}
+	if _, err := scn.Scan("1+$"); err == nil {
+		t.Fatal("expected error")
+	} else if e, ok := err.(Error); ok == false || e.Pos != 2 {
+		t.Fatalf("expected scan error at 2: %#v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 )
@@ -17,12 +18,14 @@ import (
 // Indexing tables selects rows:
 //	T[⍳5]
 // returns a table with the first 5 rows.
-// Right arrow indexing selects columns, just like a dict.
-//	T→Col1 // TODO update
+// Indexing with a column name selects a column, just like a dict.
+//	T[`Col1]
 // Sorting by column
-//	T[⍋T→Time] // TODO update
+//	T[⍋T[`Time]]
 // Selecting rows
-//	T[⍸T→Qty>5] // TODO update
+//	T[{Qty>5}]
+// Queries with select, where and by are provided by package tables:
+//	(`Sym`Total#(`Sym;{+/Qty};)) t→select⍠(`where`by#({Qty>5};`Sym;)) T
 type Table struct {
 	*Dict
 	Rows int
@@ -31,7 +34,9 @@ type Table struct {
 // String formats a table using a tabwriter.
 // Each value is printed using by it's String method, same as ⍕V.
 func (t Table) String(f Format) string {
-	if f.PP == -2 || f.PP == -3 {
+	if f.Box {
+		return boxed(f, t)
+	} else if f.PP == -2 || f.PP == -3 {
 		return t.Dict.String(f)
 	}
 	var b bytes.Buffer
@@ -54,7 +59,7 @@ func (t Table) Copy() Value {
 // for columns of the corresponding keys.
 func (t Table) Csv(f Format, L Object, w io.Writer) error {
 	cw := csv.NewWriter(w)
-	if err := t.write(f, L, csvTable{cw}); err != nil {
+	if err := t.write(f, L, csvTable{cw}, t.Rows); err != nil {
 		return err
 	}
 	cw.Flush()
@@ -63,15 +68,65 @@ func (t Table) Csv(f Format, L Object, w io.Writer) error {
 
 // WriteFormatted writes the table with a tablwriter.
 // The format of the values is given by L in the same way as for Csv.
+// If the page size is limited, the table is folded and elided, see page.go.
 func (t Table) WriteFormatted(f Format, L Object, w io.Writer) error {
+	if f.paged() {
+		return t.writePaged(f.page(), L, w)
+	}
 	tw := tabwriter.NewWriter(w, 1, 0, 1, ' ', 0)
-	if err := t.write(f, L, wsTable{tw}); err != nil {
+	if err := t.write(f, L, wsTable{tw}, t.Rows); err != nil {
 		return err
 	}
 	return tw.Flush()
 }
 
-func (t Table) write(af Format, L Object, rw rowWriter) error {
+// writePaged writes the table limited by the page size.
+// The header is repeated for each column block.
+// Only the rows that fit on the page are formatted.
+func (t Table) writePaged(f Format, L Object, w io.Writer) error {
+	rows := t.Rows
+	elided := f.PH > 0 && rows+1 > f.PH
+	if elided {
+		rows = f.PH - 2
+		if rows < 1 {
+			rows = 1
+		}
+	}
+	var c collectTable
+	if err := t.write(f, L, &c, rows); err != nil {
+		return err
+	} else if len(c) == 0 {
+		return nil
+	}
+	cols := len(c[0])
+	widths := columnWidths(c, cols)
+	blocks := columnBlocks(widths, f.PW, 0)
+	if n := len(blocks); elided == false && f.PH > 0 && len(c)*n+n-1 > f.PH {
+		elided = true
+		if k := f.PH - 1; len(c) > k && k > 1 {
+			c = c[:k]
+		}
+		widths = columnWidths(c, cols)
+	}
+	var lines []string
+	if elided == false {
+		lines = foldRows(c, widths, blocks, false, false)
+	} else {
+		more := len(blocks) > 1
+		if more {
+			blocks = columnBlocks(widths, f.PW, 1)
+		}
+		lines = append(foldRows(c, widths, blocks[:1], false, more), elision([]int{t.Rows, cols}))
+	}
+	for _, s := range lines {
+		if _, err := fmt.Fprintln(w, strings.TrimRight(s, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t Table) write(af Format, L Object, rw rowWriter, rows int) error {
 	keys := t.Keys()
 	if len(keys) == 0 {
 		return nil
@@ -104,7 +159,7 @@ func (t Table) write(af Format, L Object, rw rowWriter) error {
 		return err
 	}
 
-	for n := 0; n < t.Rows; n++ {
+	for n := 0; n < rows; n++ {
 		for i, k := range keys {
 			custom := ""
 			if colfmt != nil {
@@ -151,6 +206,14 @@ type csvTable struct {
 
 func (c csvTable) writeRow(records []string) error { return c.Writer.Write(records) }
 
+// collectTable stores the formatted rows.
+type collectTable [][]string
+
+func (c *collectTable) writeRow(records []string) error {
+	*c = append(*c, append([]string(nil), records...))
+	return nil
+}
+
 type wsTable struct {
 	*tabwriter.Writer
 }
@@ -160,12 +223,301 @@ func (w wsTable) writeRow(records []string) error {
 	return err
 }
 
+// ParseTable parses a table from a string.
+// It reads the output of ⍕T, ¯1⍕T and "csv"⍕T.
+// The fields of a row are separated by tabs (TSV), commas (CSV) or white space,
+// depending on the first line.
+// Quoted fields are always strings: csv quotes with "" as an escaped quote,
+// or go syntax for white space separated fields.
+//
+// The first row is a header, if none of it's fields is a number.
+// Otherwise the columns are named C1, C2, ….
+// A header that contains a number, e.g. year,2019, is read as a data row,
+// unless a prototype with these column names is given.
+// If all fields of a column are numbers, they are parsed and unified by the current tower,
+// e.g. to ints, floats, complex numbers or times.
+// Otherwise the column contains strings.
+//
+// If a prototype table is given, the columns are converted to the types of it's columns.
+// A header must contain the names of the prototype's columns, the order may be different.
+// Fields that cannot be converted return an error with the line and column number.
+// Lines are counted from 1 in the input, including empty lines.
 func (a *Apl) ParseTable(prototype Value, s string) (Table, error) {
+	var proto *Table
 	if prototype != nil {
-		_, ok := prototype.(Table)
+		p, ok := prototype.(Table)
 		if ok == false {
 			return Table{}, fmt.Errorf("ParseTable: prototype is not a table: %T", prototype)
 		}
+		proto = &p
+	}
+	records, lines, err := splitRecords(s)
+	if err != nil {
+		return Table{}, fmt.Errorf("parse table: %s", err)
+	} else if len(records) == 0 && proto == nil {
+		return Table{}, fmt.Errorf("parse table: no data")
+	}
+
+	var names []Value
+	if len(records) > 0 && (a.isHeader(records[0]) || isProtoHeader(proto, records[0])) {
+		for _, f := range records[0] {
+			names = append(names, String(f.s))
+		}
+		records, lines = records[1:], lines[1:]
+	} else if proto != nil {
+		names = proto.Keys()
+	} else {
+		for i := range records[0] {
+			names = append(names, String(fmt.Sprintf("C%d", i+1)))
+		}
+	}
+	for i, r := range records {
+		if len(r) != len(names) {
+			return Table{}, fmt.Errorf("parse table: line %d: expected %d fields, got %d", lines[i], len(names), len(r))
+		}
+	}
+
+	d := Dict{K: make([]Value, len(names)), M: make(map[Value]Value)}
+	for k, name := range names {
+		var col Value
+		fields := make([]tableField, len(records))
+		for i, r := range records {
+			fields[i] = r[k]
+		}
+		if proto != nil {
+			pc := proto.At(name)
+			if pc == nil {
+				return Table{}, fmt.Errorf("parse table: column %d: %s is not in the prototype", k+1, name.String(a.Format))
+			}
+			col, err = a.convertColumn(pc, fields, k, lines)
+			if err != nil {
+				return Table{}, err
+			}
+		} else {
+			col = a.inferColumn(fields)
+		}
+		d.K[k] = name
+		d.M[name] = col
+	}
+	if proto != nil && len(names) != len(proto.Keys()) {
+		return Table{}, fmt.Errorf("parse table: expected %d columns, got %d", len(proto.Keys()), len(names))
+	}
+	return Table{Dict: &d, Rows: len(records)}, nil
+}
+
+// tableField is a field of a table row and if it has been quoted.
+type tableField struct {
+	s      string
+	quoted bool
+}
+
+// isHeader returns true, if no field of the row is a number.
+func (a *Apl) isHeader(r []tableField) bool {
+	for _, f := range r {
+		if _, ok := a.parseField(f); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// isProtoHeader returns true, if the fields of the row are the column names of the prototype.
+func isProtoHeader(proto *Table, r []tableField) bool {
+	if proto == nil || len(r) != len(proto.Keys()) {
+		return false
+	}
+	for _, f := range r {
+		if f.quoted == false && proto.At(String(f.s)) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// parseField parses an unquoted field as a number of the current tower.
+// A minus sign is accepted in place of ¯.
+func (a *Apl) parseField(f tableField) (Number, bool) {
+	if f.quoted || f.s == "" {
+		return nil, false
+	}
+	if n, err := a.Tower.Parse(f.s); err == nil {
+		return n.Number, true
+	}
+	if strings.IndexByte(f.s, '-') >= 0 {
+		if n, err := a.Tower.Parse(strings.Replace(f.s, "-", "¯", -1)); err == nil {
+			return n.Number, true
+		}
+	}
+	return nil, false
+}
+
+// inferColumn returns a uniform numeric column, if all fields are numbers
+// that can be unified, otherwise a string column.
+func (a *Apl) inferColumn(fields []tableField) Array {
+	values := make([]Value, len(fields))
+	numeric := true
+	for i, f := range fields {
+		n, ok := a.parseField(f)
+		if ok == false {
+			numeric = false
+			break
+		}
+		values[i] = n
+	}
+	if numeric && len(fields) > 0 {
+		if u, ok := a.Unify(MixedArray{Dims: []int{len(values)}, Values: values}, true); ok {
+			return u
+		}
+	}
+	sa := StringArray{Dims: []int{len(fields)}, Strings: make([]string, len(fields))}
+	for i, f := range fields {
+		sa.Strings[i] = f.s
+	}
+	return sa
+}
+
+// convertColumn converts the fields to the element type of the prototype column.
+// Columns of a prototype that is not uniform are inferred.
+// Lines contains the line number of each field for error messages.
+func (a *Apl) convertColumn(pc Value, fields []tableField, k int, lines []int) (Array, error) {
+	u, ok := pc.(Uniform)
+	if ok == false {
+		return a.inferColumn(fields), nil
+	}
+	zero := u.Zero()
+	t := reflect.TypeOf(zero)
+	res := u.Make([]int{len(fields)})
+	for i, f := range fields {
+		var v Value
+		if _, ok := zero.(String); ok {
+			v = String(f.s)
+		} else if z, ok := zero.(Number); ok {
+			n, ok := a.parseField(f)
+			if ok == false {
+				return nil, fmt.Errorf("parse table: line %d column %d: not a number: %q", lines[i], k+1, f.s)
+			}
+			if _, ok := zero.(Bool); ok {
+				if x, ok := n.(Int); ok && (x == 0 || x == 1) {
+					n = Bool(x == 1)
+				}
+			}
+			if reflect.TypeOf(n) != t {
+				if x, _, err := a.Tower.SameType(n, z); err == nil {
+					n = x
+				}
+			}
+			v = n
+		} else {
+			return nil, fmt.Errorf("parse table: column %d: unsupported column type %T", k+1, zero)
+		}
+		if reflect.TypeOf(v) != t {
+			return nil, fmt.Errorf("parse table: line %d column %d: cannot convert %q to %T", lines[i], k+1, f.s, zero)
+		}
+		if err := res.Set(i, v); err != nil {
+			return nil, fmt.Errorf("parse table: line %d column %d: %s", lines[i], k+1, err)
+		}
+	}
+	return res, nil
+}
+
+// splitRecords splits the input into rows of fields.
+// The delimiter is detected from the first line, see ParseTable.
+// Empty lines are ignored.
+// It also returns the line number, at which each record starts.
+func splitRecords(s string) ([][]tableField, []int, error) {
+	var delim byte
+	first := s
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		first = s[:i]
+	}
+	if strings.IndexByte(first, '\t') >= 0 {
+		delim = '\t'
+	} else if strings.IndexByte(first, ',') >= 0 {
+		delim = ','
+	}
+	space := func(c byte) bool {
+		return c == ' ' || c == '\r' || (c == '\t' && delim != '\t')
+	}
+	skip := func(i int) int {
+		for i < len(s) && space(s[i]) {
+			i++
+		}
+		return i
+	}
+
+	var records [][]tableField
+	var lines []int
+	line := 1
+	for i := 0; i < len(s); {
+		var r []tableField
+		start := line
+		for {
+			i = skip(i)
+			if delim == 0 && (i == len(s) || s[i] == '\n') {
+				break
+			}
+			f, n, err := readField(s[i:], delim)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %s", line, err)
+			}
+			line += strings.Count(s[i:i+n], "\n")
+			i = skip(i + n)
+			r = append(r, f)
+			if i == len(s) || s[i] == '\n' {
+				break
+			} else if delim != 0 && s[i] != delim {
+				return nil, nil, fmt.Errorf("line %d: expected %q after a quoted field", line, delim)
+			} else if delim != 0 {
+				i++
+			}
+		}
+		i++
+		line++
+		if len(r) > 1 || (len(r) == 1 && (r[0].s != "" || r[0].quoted)) {
+			records = append(records, r)
+			lines = append(lines, start)
+		}
+	}
+	return records, lines, nil
+}
+
+// readField reads a single field from the start of s and returns the number of bytes consumed.
+// An unquoted field ends at the delimiter, or at white space if delim is 0.
+func readField(s string, delim byte) (tableField, int, error) {
+	if len(s) > 0 && s[0] == '"' {
+		if delim == 0 {
+			for i := 1; i < len(s); i++ {
+				if s[i] == '\\' {
+					i++
+				} else if s[i] == '"' {
+					u, err := strconv.Unquote(s[:i+1])
+					return tableField{s: u, quoted: true}, i + 1, err
+				}
+			}
+		} else {
+			var b strings.Builder
+			for i := 1; i < len(s); i++ {
+				if s[i] == '"' && i+1 < len(s) && s[i+1] == '"' {
+					b.WriteByte('"')
+					i++
+				} else if s[i] == '"' {
+					return tableField{s: b.String(), quoted: true}, i + 1, nil
+				} else {
+					b.WriteByte(s[i])
+				}
+			}
+		}
+		return tableField{}, 0, fmt.Errorf("unterminated quoted field")
+	}
+	end := func(c byte) bool {
+		if delim == 0 {
+			return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+		}
+		return c == delim || c == '\n'
+	}
+	n := 0
+	for n < len(s) && end(s[n]) == false {
+		n++
 	}
-	return Table{}, fmt.Errorf("TODO ParseTable")
+	return tableField{s: strings.TrimRight(s[:n], " \r")}, n, nil
 }
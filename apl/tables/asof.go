@@ -0,0 +1,291 @@
+package tables
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/ktye/iv/apl"
+)
+
+// aj is the as-of join.
+// The option on lists the equality keys followed by the time column:
+//
+//	Trades t→aj⍠(`on#`sym`time) Quotes
+//
+// For each row of L, it joins the last row of R with the same keys,
+// that has a time at or before the time of L.
+// Rows of L without a match are filled.
+// The result has the rows of L and the time column of L.
+func aj(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	x, err := newAsof(a, "aj", L, R)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]rowPair, x.l.Rows)
+	for i := range rows {
+		g, t := x.group(i)
+		k, err := x.search(g, t, false)
+		if err != nil {
+			return nil, err
+		}
+		rows[i] = rowPair{i, -1}
+		if k > 0 {
+			rows[i].r = g[k-1]
+		}
+	}
+	return joinRows(a, "aj", x.l, x.r, x.keys, rows)
+}
+
+// wj is the window join.
+// It aggregates the rows of R with the same keys within a time window around each row of L.
+// The options are:
+//
+//	on      the equality keys followed by the time column
+//	window  the offsets lo hi, that are added to the time of L
+//	agg     a function, that is applied to each column, or a dict of functions for each column
+//
+// Rows of R are in the window, if their time t satisfies: (lo+T) ≤ t ≤ hi+T
+//
+//	Trades t→wj⍠(`on`window`agg#(`sym`time;¯2s 0s;{⌈/⍵};)) Quotes
+//
+// The aggregation function is called with the values of a column in time order,
+// which may be empty.
+// With a single function, all columns of R that are not keys are aggregated.
+// The result has the columns of L followed by the aggregated columns.
+func wj(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	x, err := newAsof(a, "wj", L, R)
+	if err != nil {
+		return nil, err
+	}
+	lo, hi, err := window(a)
+	if err != nil {
+		return nil, err
+	}
+	names, funcs, err := aggregations(a, x)
+	if err != nil {
+		return nil, err
+	}
+
+	windows := make([][]int, x.l.Rows)
+	for i := range windows {
+		g, t := x.group(i)
+		t0, err := apl.Primitive("+").Call(a, t, lo)
+		if err != nil {
+			return nil, fmt.Errorf("wj: %s", err)
+		}
+		t1, err := apl.Primitive("+").Call(a, t, hi)
+		if err != nil {
+			return nil, fmt.Errorf("wj: %s", err)
+		}
+		start, err := x.search(g, t0, true)
+		if err != nil {
+			return nil, err
+		}
+		end, err := x.search(g, t1, false)
+		if err != nil {
+			return nil, err
+		}
+		if end > start {
+			windows[i] = g[start:end]
+		}
+	}
+
+	d := apl.Dict{M: make(map[apl.Value]apl.Value)}
+	for _, k := range x.l.Keys() {
+		d.K = append(d.K, k.Copy())
+		d.M[k.Copy()] = x.l.At(k).Copy()
+	}
+	for n, k := range names {
+		col := x.r.At(k).(apl.Array)
+		values := make([]apl.Value, len(windows))
+		for i, w := range windows {
+			sub := apl.MakeArray(col, []int{len(w)})
+			for m, j := range w {
+				if err := sub.Set(m, col.At(j).Copy()); err != nil {
+					return nil, err
+				}
+			}
+			v, err := funcs[n].Call(a, nil, sub)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		var res apl.Value = apl.MakeArray(col, []int{0})
+		if len(values) > 0 {
+			u, ok := a.Unify(apl.MixedArray{Dims: []int{len(values)}, Values: values}, true)
+			if ok == false {
+				return nil, fmt.Errorf("wj: column %s cannot be unified", k.String(a.Format))
+			}
+			res = u
+		}
+		if _, ok := d.M[k]; ok == false {
+			d.K = append(d.K, k.Copy())
+		}
+		d.M[k.Copy()] = res
+	}
+	return apl.Table{Dict: &d, Rows: x.l.Rows}, nil
+}
+
+// asof indexes the rows of R by the equality keys.
+// The rows of each group are sorted stably by time,
+// rows with the same time keep their order.
+type asof struct {
+	a      *apl.Apl
+	name   string
+	l, r   apl.Table
+	keys   []apl.Value
+	lk     []apl.Array      // equality key columns of L
+	lt, rt apl.Array        // time columns
+	groups map[string][]int // rows of R for each key
+}
+
+func newAsof(a *apl.Apl, name string, L, R apl.Value) (asof, error) {
+	x := asof{a: a, name: name}
+	var err error
+	x.l, x.r, err = tablePair(name, L, R)
+	if err != nil {
+		return x, err
+	}
+	if a.Option("on") == nil {
+		return x, fmt.Errorf("%s: option on is required: the key columns followed by the time column", name)
+	}
+	x.keys, err = keyColumns(a, name, x.l, x.r)
+	if err != nil {
+		return x, err
+	}
+	lc, err := columns(name, x.l, x.keys)
+	if err != nil {
+		return x, err
+	}
+	rc, err := columns(name, x.r, x.keys)
+	if err != nil {
+		return x, err
+	}
+	n := len(x.keys) - 1
+	x.lk, x.lt, x.rt = lc[:n], lc[n], rc[n]
+
+	x.groups = make(map[string][]int)
+	if x.r.Rows == 0 {
+		return x, nil
+	}
+	for i := 0; i < x.r.Rows; i++ {
+		k := rowKey(rc[:n], i)
+		x.groups[k] = append(x.groups[k], i)
+	}
+	for _, g := range x.groups {
+		sort.SliceStable(g, func(i, j int) bool {
+			b, e := x.less(x.rt.At(g[i]), x.rt.At(g[j]))
+			if e != nil && err == nil {
+				err = e
+			}
+			return b
+		})
+		if err != nil {
+			return x, fmt.Errorf("%s: cannot sort the time column: %s", name, err)
+		}
+	}
+	return x, nil
+}
+
+// less compares two times.
+// Numbers are converted to the same type by the tower.
+func (x asof) less(u, v apl.Value) (bool, error) {
+	if n, ok := u.(apl.Number); ok {
+		if m, ok := v.(apl.Number); ok {
+			var err error
+			if u, v, err = x.a.Tower.SameType(n, m); err != nil {
+				return false, err
+			}
+		}
+	}
+	if reflect.TypeOf(u) != reflect.TypeOf(v) {
+		return false, fmt.Errorf("cannot compare %T with %T", u, v)
+	}
+	l, ok := u.(lesser)
+	if ok == false {
+		return false, fmt.Errorf("cannot compare %T", u)
+	}
+	b, ok := l.Less(v)
+	if ok == false {
+		return false, fmt.Errorf("cannot compare %T", u)
+	}
+	return bool(b), nil
+}
+
+type lesser interface {
+	Less(apl.Value) (apl.Bool, bool)
+}
+
+// group returns the rows of R with the same keys as row i of L and the time of row i.
+func (x asof) group(i int) ([]int, apl.Value) {
+	return x.groups[rowKey(x.lk, i)], x.lt.At(i)
+}
+
+// search returns the number of leading rows in g with a time at or before t.
+// If strict is true, rows at time t are not counted.
+func (x asof) search(g []int, t apl.Value, strict bool) (int, error) {
+	var err error
+	n := sort.Search(len(g), func(k int) bool {
+		var b bool
+		var e error
+		if strict {
+			b, e = x.less(x.rt.At(g[k]), t)
+			b = !b
+		} else {
+			b, e = x.less(t, x.rt.At(g[k]))
+		}
+		if e != nil {
+			err = e
+			return true
+		}
+		return b
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: time column: %s", x.name, err)
+	}
+	return n, nil
+}
+
+// window returns the offsets of the time window given by the option window.
+func window(a *apl.Apl) (apl.Value, apl.Value, error) {
+	ar, ok := a.Option("window").(apl.Array)
+	if ok == false || ar.Size() != 2 {
+		return nil, nil, fmt.Errorf("wj: option window must contain 2 values: lo hi")
+	}
+	return ar.At(0), ar.At(1), nil
+}
+
+// aggregations returns the names of the columns of R and the function to aggregate them.
+func aggregations(a *apl.Apl, x asof) ([]apl.Value, []apl.Function, error) {
+	var names []apl.Value
+	var funcs []apl.Function
+	switch v := a.Option("agg").(type) {
+	case apl.Function:
+		iskey := make(map[apl.Value]bool)
+		for _, k := range x.keys {
+			iskey[k] = true
+		}
+		for _, k := range x.r.Keys() {
+			if iskey[k] == false {
+				names = append(names, k)
+				funcs = append(funcs, v)
+			}
+		}
+	case apl.Object:
+		for _, k := range v.Keys() {
+			f, ok := v.At(k).(apl.Function)
+			if ok == false {
+				return nil, nil, fmt.Errorf("wj: agg %s is not a function: %T", k.String(a.Format), v.At(k))
+			} else if _, ok := x.r.At(k).(apl.Array); ok == false {
+				return nil, nil, fmt.Errorf("wj: agg: right table has no column %s", k.String(a.Format))
+			}
+			names = append(names, k)
+			funcs = append(funcs, f)
+		}
+	default:
+		return nil, nil, fmt.Errorf("wj: option agg must be a function or a dict of functions: %T", v)
+	}
+	return names, funcs, nil
+}
@@ -0,0 +1,245 @@
+package tables
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ktye/iv/apl"
+)
+
+// joinType selects the rows of an equi-join.
+type joinType int
+
+const (
+	inner joinType = iota
+	left
+	outer
+)
+
+func (j joinType) String() string {
+	return [...]string{"join", "ljoin", "ojoin"}[j]
+}
+
+// join returns the equi-join of two tables on the key columns.
+// For each row of L, all matching rows of R are joined in the order of R.
+func join(jt joinType) func(*apl.Apl, apl.Value, apl.Value) (apl.Value, error) {
+	return func(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+		name := jt.String()
+		l, r, err := tablePair(name, L, R)
+		if err != nil {
+			return nil, err
+		}
+		keys, err := keyColumns(a, name, l, r)
+		if err != nil {
+			return nil, err
+		}
+		lk, err := columns(name, l, keys)
+		if err != nil {
+			return nil, err
+		}
+		rk, err := columns(name, r, keys)
+		if err != nil {
+			return nil, err
+		}
+
+		index := make(map[string][]int)
+		for i := 0; i < r.Rows; i++ {
+			k := rowKey(rk, i)
+			index[k] = append(index[k], i)
+		}
+		var rows []rowPair
+		matched := make([]bool, r.Rows)
+		for i := 0; i < l.Rows; i++ {
+			m := index[rowKey(lk, i)]
+			for _, j := range m {
+				rows = append(rows, rowPair{i, j})
+				matched[j] = true
+			}
+			if len(m) == 0 && jt != inner {
+				rows = append(rows, rowPair{i, -1})
+			}
+		}
+		if jt == outer {
+			for j, ok := range matched {
+				if ok == false {
+					rows = append(rows, rowPair{-1, j})
+				}
+			}
+		}
+		return joinRows(a, name, l, r, keys, rows)
+	}
+}
+
+// ujoin returns the rows of L followed by the rows of R.
+// The result has the columns of L followed by the columns of R that are not in L.
+// Missing values are filled.
+func ujoin(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	l, r, err := tablePair("ujoin", L, R)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]rowPair, 0, l.Rows+r.Rows)
+	for i := 0; i < l.Rows; i++ {
+		rows = append(rows, rowPair{i, -1})
+	}
+	for j := 0; j < r.Rows; j++ {
+		rows = append(rows, rowPair{-1, j})
+	}
+	return joinRows(a, "ujoin", l, r, nil, rows)
+}
+
+// rowPair is a row of the result with the row indexes of L and R.
+// A missing row is -1.
+type rowPair struct {
+	l, r int
+}
+
+// tablePair converts both arguments to tables.
+func tablePair(name string, L, R apl.Value) (apl.Table, apl.Table, error) {
+	l, ok := L.(apl.Table)
+	if ok == false {
+		return l, apl.Table{}, fmt.Errorf("%s: left argument must be a table: %T", name, L)
+	}
+	r, ok := R.(apl.Table)
+	if ok == false {
+		return l, r, fmt.Errorf("%s: right argument must be a table: %T", name, R)
+	}
+	return l, r, nil
+}
+
+// keyColumns returns the names of the key columns.
+// They are given by the variant option on, otherwise all common columns are keys.
+// Key columns must exist in both tables and have the same type.
+func keyColumns(a *apl.Apl, name string, l, r apl.Table) ([]apl.Value, error) {
+	var keys []apl.Value
+	switch v := a.Option("on").(type) {
+	case nil:
+		for _, k := range l.Keys() {
+			if r.At(k) != nil {
+				keys = append(keys, k)
+			}
+		}
+	case apl.Array:
+		for i := 0; i < v.Size(); i++ {
+			keys = append(keys, v.At(i))
+		}
+	default:
+		keys = []apl.Value{v}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s: tables have no common columns", name)
+	}
+	for _, k := range keys {
+		lc, lok := l.At(k).(apl.Array)
+		rc, rok := r.At(k).(apl.Array)
+		if lok == false || rok == false {
+			return nil, fmt.Errorf("%s: key column %s is not in both tables", name, k.String(a.Format))
+		}
+		lt, rt := elementType(lc), elementType(rc)
+		if lt != nil && rt != nil && lt != rt {
+			return nil, fmt.Errorf("%s: key column %s has different types: %s and %s", name, k.String(a.Format), lt, rt)
+		}
+	}
+	return keys, nil
+}
+
+// elementType returns the type of the elements of a column,
+// or nil if it cannot be determined.
+func elementType(col apl.Array) reflect.Type {
+	if u, ok := col.(apl.Uniform); ok {
+		return reflect.TypeOf(u.Zero())
+	} else if col.Size() == 0 {
+		return nil
+	}
+	t := reflect.TypeOf(col.At(0))
+	for i := 1; i < col.Size(); i++ {
+		if reflect.TypeOf(col.At(i)) != t {
+			return nil
+		}
+	}
+	return t
+}
+
+// columns returns the columns of a table with the given names.
+func columns(name string, t apl.Table, keys []apl.Value) ([]apl.Array, error) {
+	cols := make([]apl.Array, len(keys))
+	for i, k := range keys {
+		col, ok := t.At(k).(apl.Array)
+		if ok == false {
+			return nil, fmt.Errorf("%s: table has no column %s", name, k.String(apl.Format{}))
+		}
+		cols[i] = col
+	}
+	return cols, nil
+}
+
+// rowKey returns a string that identifies the values of a row in the given columns.
+// Values are formatted with full precision.
+func rowKey(cols []apl.Array, i int) string {
+	f := apl.Format{PP: -1}
+	s := make([]string, len(cols))
+	for k, col := range cols {
+		s[k] = col.At(i).String(f)
+	}
+	return strings.Join(s, "\x00")
+}
+
+// zero returns the fill value for missing rows of a column.
+func zero(col apl.Array) apl.Value {
+	if u, ok := col.(apl.Uniform); ok {
+		return u.Zero()
+	}
+	return apl.Int(0)
+}
+
+// joinRows builds the result table for the row pairs.
+// Key columns are taken from L, or from R if the row of L is missing.
+// Other columns that are in both tables are taken from R, if the row of R exists.
+func joinRows(a *apl.Apl, name string, l, r apl.Table, keys []apl.Value, rows []rowPair) (apl.Value, error) {
+	iskey := make(map[apl.Value]bool)
+	for _, k := range keys {
+		iskey[k] = true
+	}
+	names := append([]apl.Value{}, l.Keys()...)
+	for _, k := range r.Keys() {
+		if l.At(k) == nil {
+			names = append(names, k)
+		}
+	}
+
+	d := apl.Dict{K: make([]apl.Value, len(names)), M: make(map[apl.Value]apl.Value)}
+	for n, k := range names {
+		lc, inl := l.At(k).(apl.Array)
+		rc, inr := r.At(k).(apl.Array)
+		src := lc
+		if inl == false {
+			src = rc
+		}
+		fill := zero(src)
+		values := make([]apl.Value, len(rows))
+		for i, p := range rows {
+			switch {
+			case inr && p.r >= 0 && (iskey[k] == false || p.l < 0 || inl == false):
+				values[i] = rc.At(p.r).Copy()
+			case inl && p.l >= 0:
+				values[i] = lc.At(p.l).Copy()
+			default:
+				values[i] = fill.Copy()
+			}
+		}
+		var col apl.Value
+		if len(values) == 0 {
+			col = apl.MakeArray(src, []int{0})
+		} else {
+			u, ok := a.Unify(apl.MixedArray{Dims: []int{len(values)}, Values: values}, true)
+			if ok == false {
+				return nil, fmt.Errorf("%s: column %s cannot be unified", name, k.String(a.Format))
+			}
+			col = u
+		}
+		d.K[n] = k.Copy()
+		d.M[k.Copy()] = col
+	}
+	return apl.Table{Dict: &d, Rows: len(rows)}, nil
+}
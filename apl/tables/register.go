@@ -0,0 +1,54 @@
+// Package tables provides relational functions on tables.
+//
+// Joins combine the rows of two tables L and R by key columns:
+//
+//	L t→join R     inner join: rows of L that match a row of R
+//	L t→ljoin R    left join: all rows of L, missing values from R are filled
+//	L t→ojoin R    outer join: all rows of L and R
+//	L t→ujoin R    union join: rows of L followed by the rows of R, aligned by column names
+//
+// The key columns are all columns that are in both tables,
+// or they are given by the variant option on:
+//
+//	L t→join⍠(`on#`id`date) R
+//
+// The result contains the columns of L followed by the remaining columns of R.
+// Columns that are in both tables, but not keys, are taken from R for matching rows.
+// Missing values are filled with the zero value of the column type, 0 or an empty string.
+//
+// Time series are joined by the last key column, which is usually a time column.
+// The option on is required:
+//
+//	L t→aj⍠(`on#`sym`time) R                               as-of join
+//	L t→wj⍠(`on`window`agg#(`sym`time;¯2s 0s;{⌈/⍵};)) R     window join
+//
+// The as-of join attaches the last row of R at or before the time of each row of L.
+// The window join aggregates the rows of R within a time window around each row of L.
+//
+// Queries select columns, filter rows, group, sort and limit the result:
+//
+//	L t→select⍠(`where`by`sort`limit#({Qty>5};`Sym;`Total;10;)) T
+//
+// See selectTable in select.go for the details.
+package tables
+
+import (
+	"github.com/ktye/iv/apl"
+)
+
+// Register adds the tables package to the interpreter.
+func Register(a *apl.Apl, name string) {
+	if name == "" {
+		name = "t"
+	}
+	pkg := map[string]apl.Value{
+		"aj":     apl.ToFunction(aj),
+		"join":   apl.ToFunction(join(inner)),
+		"ljoin":  apl.ToFunction(join(left)),
+		"ojoin":  apl.ToFunction(join(outer)),
+		"select": apl.ToFunction(selectTable),
+		"ujoin":  apl.ToFunction(ujoin),
+		"wj":     apl.ToFunction(wj),
+	}
+	a.RegisterPackage(name, pkg)
+}
@@ -0,0 +1,435 @@
+package tables
+
+import (
+	"fmt"
+
+	"github.com/ktye/iv/apl"
+)
+
+// selectTable is a query on a table, similar to q-sql.
+// L is the select list, R is the table.
+// The query is given by variant options:
+//
+//	L t→select⍠(`where`by`sort`desc`limit#(...)) T
+//
+// The steps are applied in this order:
+//
+//	where  a function or a list of functions that return a boolean vector to filter the rows
+//	by     group columns: a column name, a vector of names or a dict of names and functions
+//	L      select the columns of the result
+//	sort   sort the result by a column name, a vector of names or a dict of names to directions 1 or ¯1
+//	desc   reverse the sort order if it is 1
+//	limit  the number of rows: n takes the first and -n the last rows
+//
+// The select list L is a column name, a vector of names, or a dict of names to column names or functions.
+// Without L, all columns are selected.
+//
+// Functions are called with the table as the right argument in an environment
+// where each column with a string name is a variable.
+// As all variables, column names used in functions must start with an upper case letter:
+//
+//	(`Sym`Value#(`Sym;{Qty×Px};)) t→select⍠(`where#({Qty>5};)) T
+//
+// Multiple where clauses are applied in order, each sees only the rows that passed the previous ones.
+//
+// Without a group, a function returns a vector with a value for each row, or a scalar.
+// If all columns are scalars, the result has a single row, otherwise scalars are extended.
+//
+// With a group, the result starts with the group columns followed by one row for each group,
+// in the order of their first appearance.
+// A function is called for each group and must return a scalar,
+// a column name selects the last value of each group:
+//
+//	(`N`Total#({≢⍵};{+/Qty};)) t→select⍠(`by#`Sym) T
+func selectTable(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
+	t, ok := R.(apl.Table)
+	if ok == false {
+		return nil, fmt.Errorf("select: right argument must be a table: %T", R)
+	}
+	t, err := where(a, t)
+	if err != nil {
+		return nil, err
+	}
+	names, exprs, err := selectList(L, t)
+	if err != nil {
+		return nil, err
+	}
+
+	var res apl.Table
+	if by := a.Option("by"); by != nil {
+		res, err = selectGroups(a, t, by, names, exprs)
+	} else {
+		res, err = selectColumns(a, t, names, exprs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if res, err = sortRows(a, res); err != nil {
+		return nil, err
+	}
+	return limit(a, res)
+}
+
+// where filters the rows of the table with the where clauses.
+func where(a *apl.Apl, t apl.Table) (apl.Table, error) {
+	var clauses []apl.Value
+	switch v := a.Option("where").(type) {
+	case nil:
+		return t, nil
+	case apl.List:
+		clauses = v
+	default:
+		clauses = []apl.Value{v}
+	}
+	for _, c := range clauses {
+		f, ok := c.(apl.Function)
+		if ok == false {
+			return t, fmt.Errorf("select: where clause must be a function: %T", c)
+		}
+		v, err := a.EnvCall(f, nil, t, columnVars(t))
+		if err != nil {
+			return t, fmt.Errorf("select: where: %s", err)
+		}
+		var rows []int
+		if b, ok := v.(apl.Bool); ok {
+			if b {
+				continue
+			}
+		} else if ar, ok := v.(apl.Array); ok && ar.Size() == t.Rows {
+			for i := 0; i < t.Rows; i++ {
+				b, ok := ar.At(i).(apl.Bool)
+				if ok == false {
+					return t, fmt.Errorf("select: where clause must return a boolean vector: %T", ar.At(i))
+				} else if b {
+					rows = append(rows, i)
+				}
+			}
+		} else {
+			return t, fmt.Errorf("select: where clause must return a boolean vector of length %d", t.Rows)
+		}
+		sub, err := subTable(t, rows)
+		if err != nil {
+			return t, err
+		}
+		t = sub
+	}
+	return t, nil
+}
+
+// selectList returns the names of the result columns and their expressions.
+// An expression is a column name of the table or a function.
+func selectList(L apl.Value, t apl.Table) ([]apl.Value, []apl.Value, error) {
+	var names []apl.Value
+	switch v := L.(type) {
+	case nil:
+		names = t.Keys()
+	case apl.String:
+		names = []apl.Value{v}
+	case apl.Object:
+		names = v.Keys()
+		exprs := make([]apl.Value, len(names))
+		for i, k := range names {
+			exprs[i] = function(v.At(k))
+			if _, ok := exprs[i].(apl.Function); ok {
+				continue
+			} else if t.At(exprs[i]) == nil {
+				return nil, nil, fmt.Errorf("select: column does not exist: %s", exprs[i].String(apl.Format{}))
+			}
+		}
+		return names, exprs, nil
+	case apl.Array:
+		for i := 0; i < v.Size(); i++ {
+			names = append(names, v.At(i))
+		}
+	default:
+		return nil, nil, fmt.Errorf("select: left argument must be a column name, a vector of names or a dict: %T", L)
+	}
+	for _, k := range names {
+		if t.At(k) == nil {
+			return nil, nil, fmt.Errorf("select: column does not exist: %s", k.String(apl.Format{}))
+		}
+	}
+	return names, names, nil
+}
+
+// selectColumns evaluates the select list without a group.
+func selectColumns(a *apl.Apl, t apl.Table, names, exprs []apl.Value) (apl.Table, error) {
+	values := make([]apl.Value, len(exprs))
+	rows := 1
+	for i, e := range exprs {
+		f, ok := e.(apl.Function)
+		if ok == false {
+			values[i] = t.At(e).Copy()
+			rows = t.Rows
+			continue
+		}
+		v, err := a.EnvCall(f, nil, t, columnVars(t))
+		if err != nil {
+			return apl.Table{}, fmt.Errorf("select: %s: %s", names[i].String(a.Format), err)
+		}
+		if ar, ok := v.(apl.Array); ok && len(ar.Shape()) > 0 {
+			if n := ar.Shape(); len(n) != 1 || n[0] != t.Rows {
+				return apl.Table{}, fmt.Errorf("select: %s: result has shape %v, expected %d rows", names[i].String(a.Format), n, t.Rows)
+			}
+			rows = t.Rows
+		}
+		values[i] = v
+	}
+
+	d := apl.Dict{M: make(map[apl.Value]apl.Value)}
+	for i, k := range names {
+		v := values[i]
+		if ar, ok := v.(apl.Array); ok == false || len(ar.Shape()) == 0 {
+			col := make([]apl.Value, rows)
+			for n := range col {
+				col[n] = v.Copy()
+			}
+			u, err := unify(a, names[i], col)
+			if err != nil {
+				return apl.Table{}, err
+			}
+			v = u
+		}
+		d.K = append(d.K, k.Copy())
+		d.M[k.Copy()] = v
+	}
+	return apl.Table{Dict: &d, Rows: rows}, nil
+}
+
+// selectGroups evaluates the select list for each group.
+func selectGroups(a *apl.Apl, t apl.Table, by apl.Value, names, exprs []apl.Value) (apl.Table, error) {
+	gnames, gcols, err := groupColumns(a, t, by)
+	if err != nil {
+		return apl.Table{}, err
+	}
+	isgroup := make(map[apl.Value]bool)
+	for _, k := range gnames {
+		isgroup[k] = true
+	}
+
+	var groups [][]int
+	index := make(map[string]int)
+	for i := 0; i < t.Rows; i++ {
+		k := rowKey(gcols, i)
+		n, ok := index[k]
+		if ok == false {
+			n = len(groups)
+			index[k] = n
+			groups = append(groups, nil)
+		}
+		groups[n] = append(groups[n], i)
+	}
+
+	d := apl.Dict{M: make(map[apl.Value]apl.Value)}
+	first := make([]int, len(groups))
+	for i, g := range groups {
+		first[i] = g[0]
+	}
+	for n, k := range gnames {
+		col, err := subColumn(gcols[n], first)
+		if err != nil {
+			return apl.Table{}, err
+		}
+		d.K = append(d.K, k.Copy())
+		d.M[k.Copy()] = col
+	}
+
+	sub := make([]apl.Table, len(groups))
+	for i, g := range groups {
+		if sub[i], err = subTable(t, g); err != nil {
+			return apl.Table{}, err
+		}
+	}
+	for n, e := range exprs {
+		if isgroup[names[n]] && e == names[n] {
+			continue // a group column is already in the result
+		}
+		values := make([]apl.Value, len(groups))
+		for i, g := range groups {
+			f, ok := e.(apl.Function)
+			if ok == false {
+				values[i] = t.At(e).(apl.Array).At(g[len(g)-1]).Copy()
+				continue
+			}
+			v, err := a.EnvCall(f, nil, sub[i], columnVars(sub[i]))
+			if err != nil {
+				return apl.Table{}, fmt.Errorf("select: %s: %s", names[n].String(a.Format), err)
+			}
+			if ar, ok := v.(apl.Array); ok {
+				if ar.Size() != 1 {
+					return apl.Table{}, fmt.Errorf("select: %s: result for a group must be a scalar: shape %v", names[n].String(a.Format), ar.Shape())
+				}
+				v = ar.At(0)
+			}
+			values[i] = v
+		}
+		col, err := unify(a, names[n], values)
+		if err != nil {
+			return apl.Table{}, err
+		}
+		if _, ok := d.M[names[n]]; ok == false {
+			d.K = append(d.K, names[n].Copy())
+		}
+		d.M[names[n].Copy()] = col
+	}
+	return apl.Table{Dict: &d, Rows: len(groups)}, nil
+}
+
+// groupColumns returns the names and the values of the group columns.
+// The group is given by a column name, a vector of names or a dict of names and functions.
+func groupColumns(a *apl.Apl, t apl.Table, by apl.Value) ([]apl.Value, []apl.Array, error) {
+	var names []apl.Value
+	switch v := by.(type) {
+	case apl.String:
+		names = []apl.Value{v}
+	case apl.Object:
+		names = v.Keys()
+		cols := make([]apl.Array, len(names))
+		for i, k := range names {
+			f, ok := function(v.At(k)).(apl.Function)
+			if ok == false {
+				return nil, nil, fmt.Errorf("select: by %s is not a function: %T", k.String(a.Format), v.At(k))
+			}
+			r, err := a.EnvCall(f, nil, t, columnVars(t))
+			if err != nil {
+				return nil, nil, fmt.Errorf("select: by %s: %s", k.String(a.Format), err)
+			}
+			ar, ok := r.(apl.Array)
+			if ok == false || len(ar.Shape()) != 1 || ar.Size() != t.Rows {
+				return nil, nil, fmt.Errorf("select: by %s must return a vector of length %d", k.String(a.Format), t.Rows)
+			}
+			cols[i] = ar
+		}
+		return names, cols, nil
+	case apl.Array:
+		for i := 0; i < v.Size(); i++ {
+			names = append(names, v.At(i))
+		}
+	default:
+		return nil, nil, fmt.Errorf("select: option by must be a column name, a vector of names or a dict: %T", by)
+	}
+	cols, err := columns("select", t, names)
+	return names, cols, err
+}
+
+// sortRows sorts the result by the columns given by the option sort.
+// The sort is stable, see the grade of a table in primitives/grade.go.
+func sortRows(a *apl.Apl, t apl.Table) (apl.Table, error) {
+	keys := a.Option("sort")
+	if keys == nil {
+		return t, nil
+	}
+	grade := apl.Primitive("⍋")
+	if n, ok := a.Option("desc").(apl.Number); ok {
+		if i, ok := n.ToIndex(); ok && i != 0 {
+			grade = apl.Primitive("⍒")
+		}
+	}
+	g, err := grade.Call(a, keys, t)
+	if err != nil {
+		return t, fmt.Errorf("select: sort: %s", err)
+	}
+	idx, ok := g.(apl.IntArray)
+	if ok == false {
+		return t, fmt.Errorf("select: sort: %T", g)
+	}
+	rows := make([]int, len(idx.Ints))
+	for i, n := range idx.Ints {
+		rows[i] = n - a.Origin
+	}
+	return subTable(t, rows)
+}
+
+// limit returns the first or last rows given by the option limit.
+func limit(a *apl.Apl, t apl.Table) (apl.Value, error) {
+	v := a.Option("limit")
+	if v == nil {
+		return t, nil
+	}
+	num, ok := v.(apl.Number)
+	if ok == false {
+		return nil, fmt.Errorf("select: option limit must be an integer: %T", v)
+	}
+	n, ok := num.ToIndex()
+	if ok == false {
+		return nil, fmt.Errorf("select: option limit must be an integer")
+	}
+	start := 0
+	if n < 0 {
+		n = -n
+		if n < t.Rows {
+			start = t.Rows - n
+		}
+	}
+	if n >= t.Rows {
+		return t, nil
+	}
+	rows := make([]int, n)
+	for i := range rows {
+		rows[i] = start + i
+	}
+	return subTable(t, rows)
+}
+
+// columnVars returns the columns of a table with string names as variables.
+func columnVars(t apl.Table) map[string]apl.Value {
+	vars := make(map[string]apl.Value)
+	for _, k := range t.Keys() {
+		if s, ok := k.(apl.String); ok {
+			vars[string(s)] = t.At(k).Copy()
+		}
+	}
+	return vars
+}
+
+// subTable returns a table with the given rows.
+func subTable(t apl.Table, rows []int) (apl.Table, error) {
+	d := apl.Dict{M: make(map[apl.Value]apl.Value)}
+	for _, k := range t.Keys() {
+		col, ok := t.At(k).(apl.Array)
+		if ok == false {
+			return t, fmt.Errorf("select: table column is not an array: %T", t.At(k))
+		}
+		sub, err := subColumn(col, rows)
+		if err != nil {
+			return t, err
+		}
+		d.K = append(d.K, k.Copy())
+		d.M[k.Copy()] = sub
+	}
+	return apl.Table{Dict: &d, Rows: len(rows)}, nil
+}
+
+// subColumn returns the values of a column at the given rows.
+func subColumn(col apl.Array, rows []int) (apl.Array, error) {
+	sub := apl.MakeArray(col, []int{len(rows)})
+	for i, n := range rows {
+		if err := sub.Set(i, col.At(n).Copy()); err != nil {
+			return nil, err
+		}
+	}
+	return sub, nil
+}
+
+// unify converts the values of a result column to a uniform array.
+func unify(a *apl.Apl, name apl.Value, values []apl.Value) (apl.Value, error) {
+	if len(values) == 0 {
+		return apl.MixedArray{Dims: []int{0}}, nil
+	}
+	u, ok := a.Unify(apl.MixedArray{Dims: []int{len(values)}, Values: values}, true)
+	if ok == false {
+		return nil, fmt.Errorf("select: column %s cannot be unified", name.String(a.Format))
+	}
+	return u, nil
+}
+
+// function unpacks a list with a single function.
+// This is what a dict value contains, if the dict is created with a single key: `N#({≢⍵};)
+func function(v apl.Value) apl.Value {
+	if l, ok := v.(apl.List); ok && len(l) == 1 {
+		if _, ok := l[0].(apl.Function); ok {
+			return l[0]
+		}
+	}
+	return v
+}
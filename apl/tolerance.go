@@ -0,0 +1,83 @@
+package apl
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Tolerance contains the comparison tolerances ⎕CT and ⎕DCT.
+// Numbers based on float64 compare with CT, numbers of the precise tower with DCT.
+//
+// Two numbers are tolerantly equal, if their distance is not larger than
+// the tolerance times the larger magnitude:
+//	|L-R| ≤ CT×(|L|⌈|R|)
+// A tolerance of 0 compares exactly.
+// Integers and rationals always compare exactly.
+type Tolerance struct {
+	CT  float64
+	DCT float64
+}
+
+// DefaultTolerance is the tolerance of a new interpreter.
+var DefaultTolerance = Tolerance{CT: 1E-14, DCT: 1E-28}
+
+// Equal compares two float64 values with the tolerance ⎕CT.
+func (t Tolerance) Equal(x, y float64) bool {
+	if x == y {
+		return true
+	}
+	return math.Abs(x-y) <= t.CT*math.Max(math.Abs(x), math.Abs(y))
+}
+
+// Floor returns the tolerant floor of x.
+// It is the nearest integer, if x is tolerantly equal to it, otherwise ⌊x.
+//	⎕CT←1E¯10 ⋄ ⌊0.99999999999
+func (t Tolerance) Floor(x float64) float64 {
+	if n := math.Floor(x + 0.5); t.Equal(x, n) {
+		return n
+	}
+	return math.Floor(x)
+}
+
+// Ceil returns the tolerant ceiling of x.
+func (t Tolerance) Ceil(x float64) float64 {
+	return -t.Floor(-x)
+}
+
+// setTolerance sets ⎕CT or ⎕DCT.
+// The value must be a non-negative number.
+func (a *Apl) setTolerance(name string, v Value) error {
+	n, ok := v.(Number)
+	if ok == false {
+		return fmt.Errorf("cannot set %s: %T", name, v)
+	}
+	s := strings.Replace(n.String(Format{PP: -1}), "¯", "-", -1)
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 || f >= 1 {
+		return fmt.Errorf("cannot set %s: must be a number in [0, 1)", name)
+	}
+	if name == "⎕CT" {
+		a.Tolerance.CT = f
+	} else {
+		a.Tolerance.DCT = f
+	}
+	return nil
+}
+
+// tolerance returns the value of ⎕CT or ⎕DCT as a number of the current tower.
+func (a *Apl) tolerance(name string) Value {
+	f := a.Tolerance.CT
+	if name == "⎕DCT" {
+		f = a.Tolerance.DCT
+	}
+	if f == 0 {
+		return Int(0)
+	}
+	s := strings.Replace(strconv.FormatFloat(f, 'E', -1, 64), "-", "¯", -1)
+	if n, err := a.Tower.Parse(s); err == nil {
+		return n.Number
+	}
+	return Int(0)
+}
@@ -3,9 +3,11 @@ package apl
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 type Tower struct {
+	Name    string // Name identifies the tower in a workspace, see RegisterTower.
 	Numbers map[reflect.Type]*Numeric
 	Import  func(v Number) Number       // Import Bool or Int
 	Uniform func([]Value) (Value, bool) // Values must already be uniform.
@@ -30,9 +32,32 @@ func (a *Apl) SetTower(t Tower) error {
 		}
 	}
 	a.Tower = t
+	for nt := range t.Numbers {
+		a.numtypes[nt.String()] = t
+	}
 	return nil
 }
 
+// RegisterTower registers a function that sets a numerical tower by it's name.
+// It is used by LoadWorkspace to restore the tower that was active, when the workspace was saved.
+// Towers with a parameter append it to the name separated by a space, e.g. "big 256".
+// The setter is registered with the first word and is called with the full name.
+func (a *Apl) RegisterTower(name string, set func(*Apl, string) error) {
+	a.towers[name] = set
+}
+
+// setTowerName sets the tower with the given name, if it is not active.
+func (a *Apl) setTowerName(name string) error {
+	if name == "" || name == a.Tower.Name {
+		return nil
+	}
+	set, ok := a.towers[strings.Fields(name)[0]]
+	if ok == false {
+		return fmt.Errorf("numeric tower is not registered: %s", name)
+	}
+	return set(a, name)
+}
+
 // Parse tries to parse a string as a Number, starting with the lowest number type.
 func (t Tower) Parse(s string) (NumExpr, error) {
 
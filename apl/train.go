@@ -24,6 +24,15 @@ func (t train) String(f Format) string {
 func (t train) Copy() Value { return t }
 
 func (t train) Call(a *Apl, L, R Value) (Value, error) {
+	f, err := t.split(a)
+	if err != nil {
+		return nil, err
+	}
+	return f.Call(a, L, R)
+}
+
+// split evaluates the train to an atop or a fork.
+func (t train) split(a *Apl) (Function, error) {
 	if len(t) < 2 {
 		return nil, fmt.Errorf("cannot call short train, length %d", len(t))
 	} else if len(t)%2 == 0 {
@@ -43,7 +52,7 @@ func (t train) Call(a *Apl, L, R Value) (Value, error) {
 		if len(t) > 3 {
 			f[1] = train(t[1:])
 		}
-		return f.Call(a, L, R)
+		return f, nil
 	} else {
 		// odd number: e f g h i j k → e f(g h(i j k)) ⍝ fork(fork(fork))
 		f := fork{}
@@ -61,7 +70,7 @@ func (t train) Call(a *Apl, L, R Value) (Value, error) {
 		if len(t) > 3 {
 			f[2] = train(t[2:])
 		}
-		return f.Call(a, L, R)
+		return f, nil
 	}
 }
 
@@ -72,6 +81,7 @@ func (t atop) String(f Format) string {
 }
 
 func (t atop) Call(a *Apl, L, R Value) (Value, error) {
+	defer a.restoreOptions(a.takeOptions())
 	g, ok := t[0].(Function)
 	if ok == false {
 		return nil, fmt.Errorf("atop: expected function g: %T", t[0])
@@ -96,6 +106,7 @@ func (fk fork) String(f Format) string {
 }
 
 func (fk fork) Call(a *Apl, L, R Value) (Value, error) {
+	defer a.restoreOptions(a.takeOptions())
 	f, fok := fk[0].(Function)
 
 	g, ok := fk[1].(Function)
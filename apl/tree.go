@@ -0,0 +1,577 @@
+package apl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The parse tree of a program can be converted to a List and back,
+// to inspect or generate code at runtime.
+//
+// Each node is a List, that starts with a String tag:
+//	("number";N)                 numeric constant
+//	("string";S)                 string constant
+//	("identifier";NAME)          variable, function or operator name
+//	("primitive";SYMBOL)         primitive function
+//	("operator";SYMBOL)          primitive operator
+//	("array";E…)                 strand, the empty strand is an empty array
+//	("list";E…)                  list expression (E;E;…)
+//	("index";E…)                 bracket index specification
+//	("train";F…)                 function train
+//	("function";F;L;R)           function application
+//	("derived";OP;LO;RO)         derived function
+//	("assignment";T;R;[F])       assignment to target T, optionally modified by F
+//	("lambda";G…)                lambda function with guarded expressions
+//	("guard";C;E)                guarded expression with condition C
+//	("trap";C;E)                 error guard with codes C
+//	("dop";B0;B1;B2;B3)          lambda operator
+//	("self")                     ∇
+// An EmptyArray stands for a missing argument, operand or condition.
+//
+// A lambda operator has one body for each combination of operand classes
+// (see lambdaOp). Each body is a lambda node or a String with the parse error.
+//
+// Lambdas that are created from a List have no source and cannot be stored in a workspace.
+
+// List converts the program to a List of parse tree nodes, one for each statement.
+func (p Program) List() (List, error) {
+	l := make(List, len(p))
+	for i, e := range p {
+		if v, err := treeNode(e); err != nil {
+			return nil, err
+		} else {
+			l[i] = v
+		}
+	}
+	return l, nil
+}
+
+// ParseList converts a List of parse tree nodes back to a program.
+// It is the reverse of Program.List.
+// The sandbox is checked, as if the program was parsed from source.
+func (a *Apl) ParseList(l List) (Program, error) {
+	t := tree{a: a}
+	p := make(Program, len(l))
+	for i, v := range l {
+		if e, err := t.expr(v); err != nil {
+			return nil, err
+		} else {
+			p[i] = e
+		}
+	}
+	if len(p) == 0 {
+		return nil, fmt.Errorf("empty program")
+	}
+	return p, nil
+}
+
+func node(tag string, v ...Value) List {
+	return append(List{String(tag)}, v...)
+}
+
+// treeNode converts an expression to a parse tree node.
+func treeNode(e expr) (Value, error) {
+	nodes := func(tag string, l []expr) (Value, error) {
+		n := node(tag)
+		for _, e := range l {
+			if v, err := treeNode(e); err != nil {
+				return nil, err
+			} else {
+				n = append(n, v)
+			}
+		}
+		return n, nil
+	}
+	switch x := e.(type) {
+	case nil:
+		return EmptyArray{}, nil
+	case EmptyArray:
+		return node("array"), nil
+	case NumExpr:
+		return node("number", x.Number), nil
+	case String:
+		return node("string", x), nil
+	case numVar:
+		return node("identifier", String(x.name)), nil
+	case fnVar:
+		return node("identifier", String(x)), nil
+	case opVar:
+		return node("identifier", String(x)), nil
+	case Primitive:
+		return node("primitive", String(x)), nil
+	case self:
+		return node("self"), nil
+	case array:
+		return nodes("array", x)
+	case list:
+		return nodes("list", x)
+	case idxSpec:
+		return nodes("index", x)
+	case train:
+		return nodes("train", x)
+	case *function:
+		if d, ok := x.Function.(*derived); ok && d.op == "←" {
+			// A←R is stored with the target as the operand,
+			// A f←R with the modifier as the operand and the target as the left argument.
+			if x.left == nil {
+				return nodes("assignment", []expr{d.lo, x.right})
+			}
+			return nodes("assignment", []expr{x.left, x.right, d.lo})
+		}
+		var f expr
+		if x.Function != nil {
+			var ok bool
+			if f, ok = x.Function.(expr); ok == false {
+				return nil, fmt.Errorf("cannot convert function of type %T to a list", x.Function)
+			}
+		}
+		return nodes("function", []expr{f, x.left, x.right})
+	case *derived:
+		var op Value = node("operator", String(x.op))
+		if x.dop != nil {
+			var err error
+			if op, err = treeNode(x.dop); err != nil {
+				return nil, err
+			}
+		}
+		lo, err := treeNode(x.lo)
+		if err != nil {
+			return nil, err
+		}
+		ro, err := treeNode(x.ro)
+		if err != nil {
+			return nil, err
+		}
+		return node("derived", op, lo, ro), nil
+	case *lambda:
+		return lambdaNode(x.body)
+	case *lambdaOp:
+		n := node("dop")
+		for k, b := range x.body {
+			if x.err[k] != nil {
+				n = append(n, String(x.err[k].Error()))
+			} else if v, err := lambdaNode(b); err != nil {
+				return nil, err
+			} else {
+				n = append(n, v)
+			}
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("cannot convert expression of type %T to a list", e)
+	}
+}
+
+// lambdaNode converts the body of a lambda function to a lambda node.
+func lambdaNode(body guardList) (Value, error) {
+	n := node("lambda")
+	for _, g := range body {
+		tag := "guard"
+		if g.trap {
+			tag = "trap"
+		}
+		c, err := treeNode(g.cond)
+		if err != nil {
+			return nil, err
+		}
+		e, err := treeNode(g.e)
+		if err != nil {
+			return nil, err
+		}
+		n = append(n, node(tag, c, e))
+	}
+	return n, nil
+}
+
+// tree converts parse tree nodes to expressions.
+// Within the body of a lambda operator, operands contains the classes of ⍺⍺ and ⍵⍵.
+type tree struct {
+	a        *Apl
+	operands map[string]class
+}
+
+// split returns the tag and the arguments of a node.
+// If n is not negative, the number of arguments must match.
+func (t tree) split(v Value, n int) (string, List, error) {
+	l, ok := v.(List)
+	if ok == false || len(l) == 0 {
+		return "", nil, fmt.Errorf("parse tree node must be a tagged list: %T", v)
+	}
+	tag, ok := l[0].(String)
+	if ok == false {
+		return "", nil, fmt.Errorf("parse tree node must start with a string tag: %T", l[0])
+	}
+	if n >= 0 && len(l) != n+1 {
+		return "", nil, fmt.Errorf("parse tree node %s must have %d arguments: %d", tag, n, len(l)-1)
+	}
+	return string(tag), l[1:], nil
+}
+
+// optional converts a node, that may be an EmptyArray, to nil.
+func (t tree) optional(v Value) (expr, error) {
+	if _, ok := v.(EmptyArray); ok {
+		return nil, nil
+	}
+	return t.expr(v)
+}
+
+func (t tree) exprs(l List, opt bool) ([]expr, error) {
+	v := make([]expr, len(l))
+	var err error
+	for i := range l {
+		if opt {
+			v[i], err = t.optional(l[i])
+		} else {
+			v[i], err = t.expr(l[i])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// name returns the String argument of a node.
+func (t tree) name(tag string, l List) (string, error) {
+	if len(l) != 1 {
+		return "", fmt.Errorf("parse tree node %s must have 1 argument: %d", tag, len(l))
+	}
+	s, ok := l[0].(String)
+	if ok == false || s == "" {
+		return "", fmt.Errorf("parse tree node %s must contain a string: %T", tag, l[0])
+	}
+	return string(s), nil
+}
+
+func (t tree) expr(v Value) (expr, error) {
+	tag, l, err := t.split(v, -1)
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case "number":
+		if len(l) == 1 {
+			if n, ok := l[0].(Number); ok {
+				return NumExpr{n}, nil
+			}
+		}
+		return nil, fmt.Errorf("parse tree node number must contain a number")
+	case "string":
+		if len(l) == 1 {
+			if s, ok := l[0].(String); ok {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("parse tree node string must contain a string")
+	case "identifier":
+		s, err := t.name(tag, l)
+		if err != nil {
+			return nil, err
+		}
+		return t.identifier(s)
+	case "primitive":
+		s, err := t.name(tag, l)
+		if err != nil {
+			return nil, err
+		}
+		return t.primitive(s)
+	case "self":
+		if len(l) != 0 {
+			return nil, fmt.Errorf("parse tree node self has no arguments")
+		}
+		return self{}, nil
+	case "array":
+		if len(l) == 0 {
+			return EmptyArray{}, nil
+		}
+		x, err := t.exprs(l, false)
+		if err != nil {
+			return nil, err
+		} else if len(x) == 1 {
+			return x[0], nil
+		}
+		return array(x), nil
+	case "list":
+		x, err := t.exprs(l, true)
+		return list(x), err
+	case "index":
+		x, err := t.exprs(l, false)
+		return idxSpec(x), err
+	case "train":
+		x, err := t.exprs(l, false)
+		return train(x), err
+	case "function":
+		if len(l) != 3 {
+			return nil, fmt.Errorf("parse tree node function must have 3 arguments: %d", len(l))
+		}
+		x, err := t.exprs(l, true)
+		if err != nil {
+			return nil, err
+		}
+		f, ok := x[0].(Function)
+		if ok == false {
+			return nil, fmt.Errorf("parse tree node function: not a function: %T", x[0])
+		} else if x[2] == nil {
+			return nil, fmt.Errorf("parse tree node function: right argument is missing")
+		}
+		return &function{Function: f, left: x[1], right: x[2]}, nil
+	case "derived":
+		return t.derived(l)
+	case "assignment":
+		if len(l) != 2 && len(l) != 3 {
+			return nil, fmt.Errorf("parse tree node assignment must have 2 or 3 arguments: %d", len(l))
+		}
+		x, err := t.exprs(l, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) == 2 {
+			return &function{Function: &derived{op: "←", lo: x[0]}, right: x[1]}, nil
+		}
+		return &function{Function: &derived{op: "←", lo: x[2]}, left: x[0], right: x[1]}, nil
+	case "lambda":
+		body, err := t.lambda(l)
+		if err != nil {
+			return nil, err
+		}
+		return &lambda{body: body}, nil
+	case "dop":
+		return t.dop(l)
+	default:
+		return nil, fmt.Errorf("unknown parse tree node: %s", tag)
+	}
+}
+
+// identifier returns the expression for a variable name.
+// The class of ⍺⍺ and ⍵⍵ is known from the operands.
+func (t tree) identifier(s string) (expr, error) {
+	if s == "⍺⍺" || s == "⍵⍵" {
+		if c, ok := t.operands[s]; ok && c == noun {
+			return numVar{s}, nil
+		}
+		return fnVar(s), nil
+	}
+	ok, fok := isVarname(s)
+	if ok == false {
+		return nil, fmt.Errorf("illegal variable name: %s", s)
+	} else if err := t.a.Sandbox.checkPackage(s); err != nil {
+		return nil, err
+	} else if fok == false {
+		return numVar{s}, nil
+	}
+	return fnVar(s), nil
+}
+
+func (t tree) primitive(s string) (expr, error) {
+	if _, ok := t.a.primitives[Primitive(s)]; ok {
+		if err := t.a.Sandbox.checkSymbol(s, false); err != nil {
+			return nil, err
+		}
+	} else if _, ok := t.a.operators[s]; ok && s == "∘" {
+		// ∘ is an operator, that is used as a primitive in ∘.f
+		if err := t.a.Sandbox.checkSymbol(s, true); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, fmt.Errorf("unknown symbol: %s", s)
+	}
+	return Primitive(s), nil
+}
+
+// derived converts the arguments of a derived node: (OP;LO;RO).
+// OP is an operator node, a lambda operator or the identifier of a variable that holds one.
+func (t tree) derived(l List) (expr, error) {
+	if len(l) != 3 {
+		return nil, fmt.Errorf("parse tree node derived must have 3 arguments: %d", len(l))
+	}
+	d := &derived{}
+	tag, x, err := t.split(l[0], -1)
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case "operator":
+		s, err := t.name(tag, x)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := t.a.operators[s]; ok == false && s != "←" && s != "⍂" {
+			return nil, fmt.Errorf("unknown operator: %s", s)
+		} else if err := t.a.Sandbox.checkSymbol(s, true); err != nil {
+			return nil, err
+		}
+		d.op = s
+	case "identifier":
+		s, err := t.name(tag, x)
+		if err != nil {
+			return nil, err
+		} else if ok, fok := isVarname(s); ok == false || fok == false {
+			return nil, fmt.Errorf("operator variable must be lowercase: %s", s)
+		} else if err := t.a.Sandbox.checkPackage(s); err != nil {
+			return nil, err
+		}
+		d.dop = opVar(s)
+	case "dop":
+		if d.dop, err = t.expr(l[0]); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("parse tree node derived: unknown operator node: %s", tag)
+	}
+	if d.lo, err = t.optional(l[1]); err != nil {
+		return nil, err
+	}
+	if d.ro, err = t.optional(l[2]); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// lambda converts the arguments of a lambda node to a guardList.
+func (t tree) lambda(l List) (guardList, error) {
+	body := make(guardList, len(l))
+	for i := range l {
+		tag, x, err := t.split(l[i], 2)
+		if err != nil {
+			return nil, err
+		} else if tag != "guard" && tag != "trap" {
+			return nil, fmt.Errorf("lambda must contain guard nodes: %s", tag)
+		}
+		g := guardExpr{trap: tag == "trap"}
+		if g.cond, err = t.optional(x[0]); err != nil {
+			return nil, err
+		} else if g.trap && g.cond == nil {
+			return nil, fmt.Errorf("parse tree node trap: error codes are missing")
+		}
+		if g.e, err = t.optional(x[1]); err != nil {
+			return nil, err
+		}
+		body[i] = &g
+	}
+	return body, nil
+}
+
+// dop converts the bodies of a lambda operator node.
+// The operator is dyadic, if any of it's bodies refers to ⍵⍵.
+func (t tree) dop(l List) (expr, error) {
+	if len(l) != 4 {
+		return nil, fmt.Errorf("parse tree node dop must have 4 arguments: %d", len(l))
+	}
+	op := &lambdaOp{}
+	ok := false
+	for k := range op.body {
+		if s, isstr := l[k].(String); isstr {
+			op.err[k] = fmt.Errorf("%s", s)
+			continue
+		}
+		tag, x, err := t.split(l[k], -1)
+		if err != nil {
+			return nil, err
+		} else if tag != "lambda" {
+			return nil, fmt.Errorf("parse tree node dop must contain lambda nodes: %s", tag)
+		}
+		q := tree{a: t.a, operands: map[string]class{"⍺⍺": verb, "⍵⍵": verb}}
+		if k&1 != 0 {
+			q.operands["⍺⍺"] = noun
+		}
+		if k&2 != 0 {
+			q.operands["⍵⍵"] = noun
+		}
+		if op.body[k], err = q.lambda(x); err != nil {
+			return nil, err
+		}
+		ok = true
+		if refersTo(l[k], "⍵⍵") {
+			op.dyadic = true
+		}
+	}
+	if ok == false {
+		return nil, op.err[0]
+	}
+	return op, nil
+}
+
+// refersTo returns if the node contains the identifier name.
+func refersTo(v Value, name string) bool {
+	l, ok := v.(List)
+	if ok == false || len(l) == 0 {
+		return false
+	}
+	if tag, ok := l[0].(String); ok && tag == "identifier" && len(l) == 2 {
+		if s, ok := l[1].(String); ok && string(s) == name {
+			return true
+		}
+	}
+	for _, x := range l[1:] {
+		if refersTo(x, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTree is the system function ⎕parse.
+// It parses the source string and returns a List of parse tree nodes.
+//	⎕parse 'A←1+2'
+type parseTree struct{}
+
+func (p parseTree) String(f Format) string { return "⎕parse" }
+func (p parseTree) Copy() Value            { return p }
+
+func (p parseTree) Call(a *Apl, L, R Value) (Value, error) {
+	if L != nil {
+		return nil, fmt.Errorf("⎕parse: must be called monadically")
+	}
+	var s string
+	switch v := R.(type) {
+	case String:
+		s = string(v)
+	case StringArray:
+		s = strings.Join(v.Strings, "")
+	default:
+		return nil, fmt.Errorf("⎕parse: argument must be a string: %T", R)
+	}
+	prog, err := a.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return prog.List()
+}
+
+// evalTree is the system function ⎕eval.
+// It evaluates a List of parse tree nodes or a single node and returns the last value.
+// Values of previous statements are printed, unless they are assignments.
+//	⎕eval ⎕parse 'A←1+2'
+type evalTree struct{}
+
+func (e evalTree) String(f Format) string { return "⎕eval" }
+func (e evalTree) Copy() Value            { return e }
+
+func (e evalTree) Call(a *Apl, L, R Value) (Value, error) {
+	if L != nil {
+		return nil, fmt.Errorf("⎕eval: must be called monadically")
+	}
+	l, ok := R.(List)
+	if ok == false {
+		return nil, fmt.Errorf("⎕eval: argument must be a list: %T", R)
+	}
+	if len(l) > 0 {
+		if _, ok := l[0].(String); ok {
+			l = List{l}
+		}
+	}
+	p, err := a.ParseList(l)
+	if err != nil {
+		return nil, err
+	}
+	values, err := a.EvalProgram(p)
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range values[:len(values)-1] {
+		if isAssignment(p[i]) == false {
+			fmt.Fprintln(a.GetOutput(), v.String(a.Format))
+		}
+	}
+	return values[len(values)-1], nil
+}
@@ -40,6 +40,9 @@ func (a *Apl) AssignEnv(name string, v Value, env *env) error {
 	if strings.ContainsRune(name, '→') {
 		return fmt.Errorf("cannot assign to a package variable")
 	}
+	if _, ok := sysfns[name]; ok {
+		return fmt.Errorf("cannot assign to a system function: %s", name)
+	}
 
 	// Assignment to the special variable ⎕ prints the value.
 	if name == "⎕" {
@@ -58,11 +61,16 @@ func (a *Apl) AssignEnv(name string, v Value, env *env) error {
 		return fmt.Errorf("cannot set index origin: %T", v)
 	} else if name == "⎕PP" {
 		return a.SetPP(v)
+	} else if name == "⎕CT" || name == "⎕DCT" {
+		return a.setTolerance(name, v)
+	} else if name == "⎕PW" || name == "⎕PH" {
+		return a.setPage(name, v)
 	}
 
-	if _, ok := v.(Function); ok && isfunc != true {
+	_, isop := v.(*lambdaOp)
+	if _, ok := v.(Function); (ok || isop) && isfunc != true {
 		return fmt.Errorf("cannot assign a function to an uppercase variable")
-	} else if ok == false && isfunc == true {
+	} else if ok == false && isop == false && isfunc == true {
 		return fmt.Errorf("only functions can be assigned to lowercase variables")
 	}
 
@@ -72,11 +80,18 @@ func (a *Apl) AssignEnv(name string, v Value, env *env) error {
 
 	// Special case: Default left argument in lambda expressions:
 	// Do not overwrite the given argument.
-	if name == "⍺" && env.vars["⍺"] != nil {
+	if l, _ := env.get("⍺"); name == "⍺" && l != nil {
 		return nil
 	}
 
-	env.vars[name] = v
+	// Lambda functions and operators are named after the first variable they are assigned to.
+	if λ, ok := v.(*lambda); ok && λ.name == "" {
+		λ.name = name
+	} else if op, ok := v.(*lambdaOp); ok && op.name == "" {
+		op.name = name
+	}
+
+	env.set(name, v)
 	return nil
 }
 
@@ -94,7 +109,16 @@ func (a *Apl) LookupEnv(name string) (Value, *env) {
 	if name == "⎕IO" {
 		return Int(a.Origin), nil
 	} else if name == "⎕PP" {
+		if a.Format.Box {
+			return Int(-4), nil
+		}
 		return Int(a.Format.PP), nil
+	} else if name == "⎕CT" || name == "⎕DCT" {
+		return a.tolerance(name), nil
+	} else if name == "⎕PW" || name == "⎕PH" {
+		return a.pageSize(name), nil
+	} else if f, ok := sysfns[name]; ok {
+		return f, nil
 	}
 
 	if idx := strings.Index(name, "→"); idx != -1 {
@@ -108,7 +132,7 @@ func (a *Apl) LookupEnv(name string) (Value, *env) {
 
 	e := a.env
 	for {
-		v, ok := e.vars[name]
+		v, ok := e.get(name)
 		if ok {
 			return v, e
 		}
@@ -138,9 +162,7 @@ func (a *Apl) Vars(pkg string) ([]string, error) {
 			return nil, fmt.Errorf("package %s is not registered", pkg)
 		}
 	}
-	for n := range e.vars {
-		l = append(l, n)
-	}
+	l = append(l, e.names()...)
 	sort.Strings(l)
 	return l, nil
 }
@@ -156,7 +178,8 @@ func (a *Apl) packageVar(name string) Value {
 	if ok == false {
 		return nil
 	}
-	return pkg.vars[varname]
+	v, _ := pkg.get(varname)
+	return v
 }
 
 // NumVar contains the identifier to a value.
@@ -180,7 +203,8 @@ func (v numVar) Eval(a *Apl) (Value, error) {
 
 // FnValue contains the identifier to a function value.
 // It's name is lowercase.
-// FnVar evaluates to the stored value or to an Identifier if it is undeclared.
+// FnVar evaluates to the stored lambda function, which carries it's closure.
+// Otherwise it evaluates to itself and is resolved when it is called.
 type fnVar string
 
 func (f fnVar) String(af Format) string {
@@ -189,6 +213,9 @@ func (f fnVar) String(af Format) string {
 func (f fnVar) Copy() Value { return f }
 
 func (f fnVar) Eval(a *Apl) (Value, error) {
+	if λ, ok := a.Lookup(string(f)).(*lambda); ok {
+		return λ, nil
+	}
 	return f, nil
 }
 
@@ -207,8 +234,19 @@ func (f fnVar) Call(a *Apl, l, r Value) (Value, error) {
 	return fn.Call(a, l, r)
 }
 
+// sysfns are the system functions.
+// Their names start with ⎕ followed by a lowercase letter.
+var sysfns = map[string]Value{
+	"⎕eval":   evalTree{},
+	"⎕fmt":    picture{},
+	"⎕parse":  parseTree{},
+	"⎕signal": signal{},
+}
+
 // isVarname returns if the string is allowed as a variable name and
 // referes to a number or function value.
+// System variables starting with ⎕ are nouns, if the next letter is uppercase,
+// and system functions otherwise.
 func isVarname(s string) (ok, isfunc bool) {
 	if s == "" {
 		return false, false
@@ -225,5 +263,8 @@ func isVarname(s string) (ok, isfunc bool) {
 			upper = true
 		}
 	}
+	if r := []rune(s); len(r) > 1 && r[0] == '⎕' && unicode.IsLower(r[1]) {
+		upper = false
+	}
 	return true, upper == false
 }
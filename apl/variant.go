@@ -0,0 +1,53 @@
+package apl
+
+// Options returns the variant options of the current call.
+// Options are attached to a function with the variant operator f⍠D, where D is a dict.
+// They are visible only to f itself, not to the primitives, operators and
+// functions that f calls in turn, e.g. the body of a lambda function.
+// The options of an inner variant take precedence over an outer one.
+//
+// Options returns nil, if there are no options.
+func (a *Apl) Options() []Object {
+	if a.pending != nil {
+		return a.pending
+	}
+	return a.options
+}
+
+// Option returns the value of a single variant option, or nil if it is not set.
+func (a *Apl) Option(name string) Value {
+	key := String(name)
+	options := a.Options()
+	for i := len(options) - 1; i >= 0; i-- {
+		if v := options[i].At(key); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// CallVariant calls f with the options attached.
+// Options that are attached to the variant itself are passed on to f.
+func (a *Apl) CallVariant(f Function, options Object, L, R Value) (Value, error) {
+	save := a.pending
+	n := len(a.options)
+	a.pending = append(a.options[:n:n], options)
+	v, err := f.Call(a, L, R)
+	a.pending = save
+	return v, err
+}
+
+// takeOptions is called when a function is entered.
+// The options of a pending variant call become the options of the function
+// and are hidden from the functions it calls.
+// It returns the options of the caller, which are restored by restoreOptions.
+//	defer a.restoreOptions(a.takeOptions())
+func (a *Apl) takeOptions() []Object {
+	options := a.options
+	a.options, a.pending = a.pending, nil
+	return options
+}
+
+func (a *Apl) restoreOptions(options []Object) {
+	a.options = options
+}
@@ -0,0 +1,496 @@
+package apl
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// A workspace stores the state of the interpreter in a single json file.
+// It contains the variables of the root environment, ⎕IO, ⎕PP, ⎕CT, ⎕DCT, ⎕PW, ⎕PH and the format strings,
+// the name of the numerical tower and all packages.
+//
+// Lambda functions and operators are stored as source and evaluated on load
+// in the root or package environment.
+// Lambdas without source, e.g. built by ⎕eval from a parse tree, are not saved.
+// A closure over a local environment is not restored.
+//
+// Values are stored with a type tag.
+// Numbers are tagged by their go type and must implement
+// encoding.TextMarshaler and encoding.TextUnmarshaler.
+//
+// Packages that are registered from go are not saved.
+// They must be registered, before the workspace is loaded.
+type workspace struct {
+	Origin   int                   `json:"io"`
+	PP       int                   `json:"pp"`
+	CT       *Tolerance            `json:"ct,omitempty"`
+	PW       int                   `json:"pw,omitempty"`
+	PH       int                   `json:"ph,omitempty"`
+	Fmt      map[string]string     `json:"fmt,omitempty"`
+	Tower    string                `json:"tower,omitempty"`
+	Vars     map[string]*wsValue   `json:"vars,omitempty"`
+	Packages map[string]*wsPackage `json:"packages,omitempty"`
+}
+
+type wsPackage struct {
+	Native bool                `json:"native,omitempty"` // registered from go
+	Vars   map[string]*wsValue `json:"vars,omitempty"`
+}
+
+// wsValue is a type tagged value.
+// Tags are: bool int string lambda operator empty array uniform list dict table
+// and the go type of a number, e.g. numbers.Float.
+type wsValue struct {
+	T     string     `json:"t"`
+	S     string     `json:"s,omitempty"`     // scalar as text, lambda source, element tag of a uniform array
+	Shape []int      `json:"shape,omitempty"` // array shape
+	A     []string   `json:"a,omitempty"`     // uniform array elements
+	V     []*wsValue `json:"v,omitempty"`     // array or list elements, dict values
+	K     []*wsValue `json:"k,omitempty"`     // dict keys
+	Rows  int        `json:"rows,omitempty"`  // table rows
+}
+
+// SaveWorkspace writes the interpreter state to w.
+// It returns the names of variables that could not be saved, such as channels
+// or functions that are not lambda expressions.
+func (a *Apl) SaveWorkspace(w io.Writer) ([]string, error) {
+	ws := workspace{
+		Origin:   a.Origin,
+		PP:       a.Format.PP,
+		CT:       &a.Tolerance,
+		PW:       a.Format.PW,
+		PH:       a.Format.PH,
+		Fmt:      make(map[string]string),
+		Tower:    a.Tower.Name,
+		Packages: make(map[string]*wsPackage),
+	}
+	for t, s := range a.Format.Fmt {
+		ws.Fmt[t.String()] = s
+	}
+	var skipped []string
+	ws.Vars, skipped = a.saveEnv(a.root(), "")
+	for name, e := range a.pkg {
+		vars, skip := a.saveEnv(e, name+"→")
+		if len(skip) > 0 {
+			ws.Packages[name] = &wsPackage{Native: true}
+		} else {
+			ws.Packages[name] = &wsPackage{Vars: vars}
+		}
+	}
+	sort.Strings(skipped)
+	return skipped, json.NewEncoder(w).Encode(ws)
+}
+
+// LoadWorkspace restores the interpreter state from a workspace written by SaveWorkspace.
+// All variables of the root environment are replaced.
+// Loading is atomic: if a value cannot be restored, the previous state is kept.
+func (a *Apl) LoadWorkspace(r io.Reader) error {
+	var ws workspace
+	if err := json.NewDecoder(r).Decode(&ws); err != nil {
+		return fmt.Errorf("load workspace: %s", err)
+	}
+	root := a.root()
+	root.mu.RLock()
+	vars := root.vars
+	root.mu.RUnlock()
+	pkg := make(map[string]*env, len(a.pkg))
+	for name, e := range a.pkg {
+		pkg[name] = e
+	}
+	tower, origin, format, tolerance := a.Tower.Name, a.Origin, a.Format, a.Tolerance
+
+	if err := a.loadWorkspace(&ws, root); err != nil {
+		a.setTowerName(tower)
+		a.Origin, a.Format, a.Tolerance = origin, format, tolerance
+		root.mu.Lock()
+		root.vars = vars
+		root.mu.Unlock()
+		a.pkg = pkg
+		return fmt.Errorf("load workspace: %s", err)
+	}
+	return nil
+}
+
+// loadWorkspace sets the interpreter state from the decoded workspace.
+// Packages are loaded into new environments and the root variables into a new map,
+// such that the caller can restore the previous state on error.
+func (a *Apl) loadWorkspace(ws *workspace, root *env) error {
+	if err := a.setTowerName(ws.Tower); err != nil {
+		return err
+	}
+	a.Origin = ws.Origin
+	a.Format.PP = ws.PP
+	a.Format.PW, a.Format.PH = ws.PW, ws.PH
+	a.Tolerance = DefaultTolerance
+	if ws.CT != nil {
+		a.Tolerance = *ws.CT
+	}
+	a.Format.Fmt = make(map[reflect.Type]string)
+	for name, s := range ws.Fmt {
+		if t := a.typeByName(name); t != nil {
+			a.Format.Fmt[t] = s
+		}
+	}
+
+	for name, p := range ws.Packages {
+		old, ok := a.pkg[name]
+		if p.Native {
+			if ok == false {
+				return fmt.Errorf("package %s is not registered", name)
+			}
+			continue
+		}
+		e := newEnv()
+		if ok {
+			for _, n := range old.names() {
+				v, _ := old.get(n)
+				e.vars[n] = v
+			}
+		}
+		if err := a.loadEnv(e, p.Vars); err != nil {
+			return fmt.Errorf("%s→%s", name, err)
+		}
+		a.pkg[name] = e
+	}
+
+	root.mu.Lock()
+	root.vars = make(map[string]Value)
+	root.mu.Unlock()
+	return a.loadEnv(root, ws.Vars)
+}
+
+// root returns the root environment.
+func (a *Apl) root() *env {
+	e := a.env
+	for e.parent != nil {
+		e = e.parent
+	}
+	return e
+}
+
+// saveEnv encodes all variables of the environment.
+// It returns the names of the variables that cannot be encoded, with the given prefix.
+func (a *Apl) saveEnv(e *env, prefix string) (map[string]*wsValue, []string) {
+	m := make(map[string]*wsValue)
+	var skipped []string
+	for _, name := range e.names() {
+		v, _ := e.get(name)
+		if wv, err := a.encodeValue(v); err != nil {
+			skipped = append(skipped, prefix+name)
+		} else {
+			m[name] = wv
+		}
+	}
+	return m, skipped
+}
+
+// loadEnv assigns the variables to the environment.
+// Lambda operators are evaluated first, as lambda functions may refer to them.
+func (a *Apl) loadEnv(e *env, vars map[string]*wsValue) error {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sort.SliceStable(names, func(i, j int) bool {
+		return vars[names[i]].T == "operator" && vars[names[j]].T != "operator"
+	})
+
+	save := a.env
+	a.env = e
+	defer func() { a.env = save }()
+	for _, name := range names {
+		w := vars[name]
+		if w.T == "lambda" || w.T == "operator" {
+			if err := a.ParseAndEval(name + "←" + w.S); err != nil {
+				return fmt.Errorf("%s: %s", name, err)
+			}
+			continue
+		}
+		v, err := a.decodeValue(w)
+		if err != nil {
+			return fmt.Errorf("%s: %s", name, err)
+		}
+		e.set(name, v)
+	}
+	return nil
+}
+
+func (a *Apl) encodeValue(v Value) (*wsValue, error) {
+	switch x := v.(type) {
+	case *lambda:
+		if x.src == "" {
+			return nil, fmt.Errorf("lambda has no source")
+		}
+		return &wsValue{T: "lambda", S: x.src}, nil
+	case *lambdaOp:
+		if x.src == "" {
+			return nil, fmt.Errorf("lambda operator has no source")
+		}
+		return &wsValue{T: "operator", S: x.src}, nil
+	case EmptyArray:
+		return &wsValue{T: "empty"}, nil
+	case List:
+		w := &wsValue{T: "list", V: make([]*wsValue, len(x))}
+		for i := range x {
+			e, err := a.encodeValue(x[i])
+			if err != nil {
+				return nil, err
+			}
+			w.V[i] = e
+		}
+		return w, nil
+	case *Dict:
+		return a.encodeDict(x, "dict")
+	case Table:
+		w, err := a.encodeDict(x.Dict, "table")
+		if err != nil {
+			return nil, err
+		}
+		w.Rows = x.Rows
+		return w, nil
+	case Array:
+		return a.encodeArray(x)
+	}
+	if t, s, err := a.encodeScalar(v); err != nil {
+		return nil, err
+	} else {
+		return &wsValue{T: t, S: s}, nil
+	}
+}
+
+func (a *Apl) encodeDict(d *Dict, tag string) (*wsValue, error) {
+	w := &wsValue{T: tag}
+	if d == nil {
+		return w, nil
+	}
+	for _, k := range d.K {
+		kv, err := a.encodeValue(k)
+		if err != nil {
+			return nil, err
+		}
+		vv, err := a.encodeValue(d.M[k])
+		if err != nil {
+			return nil, err
+		}
+		w.K = append(w.K, kv)
+		w.V = append(w.V, vv)
+	}
+	return w, nil
+}
+
+// encodeArray stores uniform arrays as a list of strings with a single element tag.
+// Other arrays store each element as a value.
+func (a *Apl) encodeArray(ar Array) (*wsValue, error) {
+	n := ar.Size()
+	if _, ok := ar.(Uniform); ok && n > 0 {
+		w := &wsValue{T: "uniform", Shape: CopyShape(ar), A: make([]string, n)}
+		for i := 0; i < n; i++ {
+			t, s, err := a.encodeScalar(ar.At(i))
+			if err != nil {
+				return nil, err
+			} else if i > 0 && t != w.S {
+				return nil, fmt.Errorf("array is not uniform: %T", ar)
+			}
+			w.S = t
+			w.A[i] = s
+		}
+		return w, nil
+	}
+	w := &wsValue{T: "array", Shape: CopyShape(ar), V: make([]*wsValue, n)}
+	for i := 0; i < n; i++ {
+		e, err := a.encodeValue(ar.At(i))
+		if err != nil {
+			return nil, err
+		}
+		w.V[i] = e
+	}
+	return w, nil
+}
+
+// encodeScalar returns the tag and the text representation of a scalar value.
+func (a *Apl) encodeScalar(v Value) (string, string, error) {
+	switch x := v.(type) {
+	case Bool:
+		if x {
+			return "bool", "1", nil
+		}
+		return "bool", "0", nil
+	case Int:
+		return "int", strconv.Itoa(int(x)), nil
+	case String:
+		return "string", string(x), nil
+	}
+	if _, ok := v.(Number); ok {
+		if m, ok := v.(encoding.TextMarshaler); ok {
+			b, err := m.MarshalText()
+			if err != nil {
+				return "", "", err
+			}
+			return reflect.TypeOf(v).String(), string(b), nil
+		}
+	}
+	return "", "", fmt.Errorf("cannot save value of type %T", v)
+}
+
+func (a *Apl) decodeValue(w *wsValue) (Value, error) {
+	switch w.T {
+	case "lambda", "operator":
+		return a.decodeLambda(w.S)
+	case "empty":
+		return EmptyArray{}, nil
+	case "list":
+		l := make(List, len(w.V))
+		for i := range w.V {
+			v, err := a.decodeValue(w.V[i])
+			if err != nil {
+				return nil, err
+			}
+			l[i] = v
+		}
+		return l, nil
+	case "dict":
+		return a.decodeDict(w)
+	case "table":
+		d, err := a.decodeDict(w)
+		if err != nil {
+			return nil, err
+		}
+		return Table{Dict: d, Rows: w.Rows}, nil
+	case "uniform":
+		switch w.S {
+		case "bool":
+			b := BoolArray{Dims: w.Shape, Bools: make([]bool, len(w.A))}
+			for i, s := range w.A {
+				b.Bools[i] = s == "1"
+			}
+			return b, nil
+		case "int":
+			n := IntArray{Dims: w.Shape, Ints: make([]int, len(w.A))}
+			for i, s := range w.A {
+				v, err := strconv.Atoi(s)
+				if err != nil {
+					return nil, err
+				}
+				n.Ints[i] = v
+			}
+			return n, nil
+		case "string":
+			return StringArray{Dims: w.Shape, Strings: w.A}, nil
+		}
+		values := make([]Value, len(w.A))
+		for i, s := range w.A {
+			v, err := a.decodeScalar(w.S, s)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		// The uniform array is created by the tower of the number type,
+		// which may not be active.
+		if mk := a.numtypes[w.S].Uniform; mk != nil {
+			if u, ok := mk(values); ok {
+				if rs, ok := u.(Reshaper); ok {
+					return rs.Reshape(w.Shape), nil
+				}
+			}
+		}
+		return MixedArray{Dims: w.Shape, Values: values}, nil
+	case "array":
+		m := NewMixed(w.Shape)
+		for i := range w.V {
+			v, err := a.decodeValue(w.V[i])
+			if err != nil {
+				return nil, err
+			}
+			m.Values[i] = v
+		}
+		return m, nil
+	}
+	return a.decodeScalar(w.T, w.S)
+}
+
+// decodeLambda evaluates the source of a lambda function or operator that is stored
+// within a list or a dict. It captures the current environment.
+func (a *Apl) decodeLambda(src string) (Value, error) {
+	p, err := a.Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	v, err := a.EvalProgram(p)
+	if err != nil {
+		return nil, err
+	} else if len(v) != 1 {
+		return nil, fmt.Errorf("lambda source is not a single expression: %s", src)
+	}
+	switch v[0].(type) {
+	case *lambda, *lambdaOp:
+		return v[0], nil
+	}
+	return nil, fmt.Errorf("source is not a lambda expression: %s", src)
+}
+
+func (a *Apl) decodeDict(w *wsValue) (*Dict, error) {
+	if len(w.K) != len(w.V) {
+		return nil, fmt.Errorf("dict keys and values do not match")
+	}
+	d := &Dict{}
+	for i := range w.K {
+		k, err := a.decodeValue(w.K[i])
+		if err != nil {
+			return nil, err
+		}
+		v, err := a.decodeValue(w.V[i])
+		if err != nil {
+			return nil, err
+		}
+		d.Set(k, v)
+	}
+	return d, nil
+}
+
+func (a *Apl) decodeScalar(tag, s string) (Value, error) {
+	switch tag {
+	case "bool":
+		return Bool(s == "1"), nil
+	case "int":
+		n, err := strconv.Atoi(s)
+		return Int(n), err
+	case "string":
+		return String(s), nil
+	}
+	t := a.typeByName(tag)
+	if t == nil {
+		return nil, fmt.Errorf("unknown type: %s", tag)
+	}
+	p := reflect.New(t)
+	u, ok := p.Interface().(encoding.TextUnmarshaler)
+	if ok == false {
+		return nil, fmt.Errorf("cannot load number type %s", tag)
+	}
+	if err := u.UnmarshalText([]byte(s)); err != nil {
+		return nil, err
+	}
+	return p.Elem().Interface().(Value), nil
+}
+
+// typeByName returns a basic type or the go type of a number in any tower
+// that has been set, or nil.
+func (a *Apl) typeByName(name string) reflect.Type {
+	for _, v := range []Value{Bool(false), Int(0), String("")} {
+		if t := reflect.TypeOf(v); t.String() == name {
+			return t
+		}
+	}
+	for t := range a.numtypes[name].Numbers {
+		if t.String() == name {
+			return t
+		}
+	}
+	return nil
+}
@@ -24,12 +24,20 @@ func (f Function) Copy() apl.Value { return f }
 // If the function returns an error as the last value, it is checked and returned.
 // Otherwise, or if the error is nil the result is converted and returned.
 // More than one result will be returned as a List.
+//
+// If the last argument has the type Options, it receives the variant options
+// of the call and is not counted as an argument.
 func (f Function) Call(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 	errarg := func(i int, err error) error {
 		return fmt.Errorf("function %s argument %d: %s", f.Name, i+1, err)
 	}
 	t := f.Fn.Type()
 	args := t.NumIn()
+	var opts []reflect.Value
+	if args > 0 && t.In(args-1) == reflect.TypeOf(Options(nil)) {
+		args--
+		opts = []reflect.Value{reflect.ValueOf(options(a))}
+	}
 	in := make([]reflect.Value, args)
 	var err error
 	if args == 0 {
@@ -67,7 +75,7 @@ func (f Function) Call(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 			}
 		}
 	}
-	out := f.Fn.Call(in)
+	out := f.Fn.Call(append(in, opts...))
 
 	// Test if the last output value is an error, check and remove it.
 	if len(out) > 0 {
@@ -96,3 +104,19 @@ func (f Function) Call(a *apl.Apl, L, R apl.Value) (apl.Value, error) {
 		return res, nil
 	}
 }
+
+// Options contains the variant options of a call, that are set with the variant operator ⍠.
+// A go function receives them, if its last argument has this type.
+type Options map[string]apl.Value
+
+// options collects the variant options of the current call.
+// Inner options overwrite outer ones.
+func options(a *apl.Apl) Options {
+	o := make(Options)
+	for _, d := range a.Options() {
+		for _, k := range d.Keys() {
+			o[k.String(a.Format)] = d.At(k)
+		}
+	}
+	return o
+}
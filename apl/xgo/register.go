@@ -18,6 +18,7 @@ func Register(a *apl.Apl, name string) {
 		"i":      New(reflect.TypeOf(I(0))),
 		"source": source{},
 		"echo":   echo{},
+		"join":   Function{Name: "join", Fn: reflect.ValueOf(join)},
 	}
 	a.RegisterPackage("go", pkg)
 }
@@ -54,6 +55,17 @@ func (s S) Sum() int {
 	return s.A + s.B
 }
 
+// join joins the strings with the separator given as the variant option sep.
+// It is an example for a function that receives options:
+//	go→join⍠(`sep#'-')`a`b`c
+func join(v []string, o Options) string {
+	sep := " "
+	if s, ok := o["sep"].(apl.String); ok {
+		sep = string(s)
+	}
+	return strings.Join(v, sep)
+}
+
 // source returns a Channel to pull numbers from.
 // It stops if the max value is reached or the channel is closed.
 // It is used for demonstrating apl.Channel.
@@ -33,14 +33,57 @@ func Apl(a *apl.Apl, stdin io.Reader, args []string) error {
 	}
 
 	// Run interactively.
+	// The output of large arrays is limited, unless the page size is set, see ⎕PW and ⎕PH.
+	if a.Format.PW == 0 && a.Format.PH == 0 {
+		a.Format.PW, a.Format.PH = 80, 40
+	}
 	scanner := bufio.NewScanner(stdin)
+	if a.Debugger == nil {
+		a.Debugger = &apl.Debugger{}
+	}
+	a.Debugger.Break = func(a *apl.Apl, stack []apl.Frame) error {
+		return breakpoint(a, scanner, stack)
+	}
 	fmt.Printf("        ")
 	for scanner.Scan() {
 		s := scanner.Text()
 		if err := a.ParseAndEval(s); err != nil {
-			fmt.Println(err)
+			printError(a, err)
 		}
 		fmt.Printf("        ")
 	}
 	return nil
 }
+
+// printError prints the error with the source position and the call stack, if it is known.
+func printError(a *apl.Apl, err error) {
+	fmt.Println(err)
+	if e, ok := err.(apl.Error); ok {
+		if c := e.Pos.Caret(); c != "" {
+			fmt.Println(c)
+		}
+		fmt.Print(apl.StackTrace(e.Stack, a.Format))
+	}
+}
+
+// breakpoint runs a nested repl in the environment of the lambda function, that is stopped.
+// An empty line continues the evaluation, → aborts it.
+func breakpoint(a *apl.Apl, scanner *bufio.Scanner, stack []apl.Frame) error {
+	name := stack[len(stack)-1].Name
+	fmt.Printf("breakpoint: %s (empty line continues, → aborts)\n", name)
+	fmt.Print(apl.StackTrace(stack, a.Format))
+	fmt.Printf("%s     ", name)
+	for scanner.Scan() {
+		s := scanner.Text()
+		if s == "" {
+			return nil
+		} else if s == "→" {
+			return fmt.Errorf("breakpoint: %s: aborted", name)
+		}
+		if err := a.ParseAndEval(s); err != nil {
+			printError(a, err)
+		}
+		fmt.Printf("%s     ", name)
+	}
+	return fmt.Errorf("breakpoint: %s: end of input", name)
+}
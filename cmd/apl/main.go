@@ -10,10 +10,12 @@ import (
 	"os"
 
 	"github.com/ktye/iv/apl"
+	aplpkg "github.com/ktye/iv/apl/a"
 	"github.com/ktye/iv/apl/big"
 	"github.com/ktye/iv/apl/numbers"
 	"github.com/ktye/iv/apl/operators"
 	"github.com/ktye/iv/apl/primitives"
+	"github.com/ktye/iv/apl/tables"
 	"github.com/ktye/iv/cmd"
 )
 
@@ -32,5 +34,7 @@ func newApl() *apl.Apl {
 	big.Register(a, "")
 	primitives.Register(a)
 	operators.Register(a)
+	aplpkg.Register(a, "")
+	tables.Register(a, "")
 	return a
 }